@@ -0,0 +1,101 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+)
+
+// allowClient wraps a SheetsClient and rejects mutations of sheets that
+// aren't in an allow-list with ErrTableNotAllowed. Reads pass through.
+type allowClient struct {
+	next    SheetsClient
+	allowed map[string]bool
+}
+
+func newAllowClient(next SheetsClient, tables []string) *allowClient {
+	allowed := make(map[string]bool, len(tables))
+	for _, name := range tables {
+		allowed[name] = true
+	}
+	return &allowClient{next: next, allowed: allowed}
+}
+
+// check returns ErrTableNotAllowed unless the sheet may be changed.
+func (c *allowClient) check(sheet string) error {
+	if !c.allowed[sheet] {
+		return fmt.Errorf("%w: %s", ErrTableNotAllowed, sheet)
+	}
+	return nil
+}
+
+func (c *allowClient) Read(ctx context.Context, range_ string) ([][]interface{}, error) {
+	return c.next.Read(ctx, range_)
+}
+
+func (c *allowClient) BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+	return c.next.BatchRead(ctx, ranges)
+}
+
+func (c *allowClient) Write(ctx context.Context, range_ string, values [][]interface{}) error {
+	if err := c.check(sheetOf(range_)); err != nil {
+		return err
+	}
+	return c.next.Write(ctx, range_, values)
+}
+
+// BatchWrite rejects the whole batch if any of its ranges is on a sheet
+// that isn't allowed, so nothing is written.
+func (c *allowClient) BatchWrite(ctx context.Context, data map[string][][]interface{}) error {
+	for range_ := range data {
+		if err := c.check(sheetOf(range_)); err != nil {
+			return err
+		}
+	}
+	return c.next.BatchWrite(ctx, data)
+}
+
+func (c *allowClient) Append(ctx context.Context, range_ string, values [][]interface{}) error {
+	if err := c.check(sheetOf(range_)); err != nil {
+		return err
+	}
+	return c.next.Append(ctx, range_, values)
+}
+
+func (c *allowClient) Clear(ctx context.Context, range_ string) error {
+	if err := c.check(sheetOf(range_)); err != nil {
+		return err
+	}
+	return c.next.Clear(ctx, range_)
+}
+
+func (c *allowClient) DeleteRows(ctx context.Context, sheetName string, rowIndices []int) error {
+	if err := c.check(sheetName); err != nil {
+		return err
+	}
+	return c.next.DeleteRows(ctx, sheetName, rowIndices)
+}
+
+func (c *allowClient) ListSheets(ctx context.Context) ([]SheetProperties, error) {
+	return c.next.ListSheets(ctx)
+}
+
+func (c *allowClient) AddSheet(ctx context.Context, sheetName string) error {
+	if err := c.check(sheetName); err != nil {
+		return err
+	}
+	return c.next.AddSheet(ctx, sheetName)
+}
+
+func (c *allowClient) DeleteSheet(ctx context.Context, sheetName string) error {
+	if err := c.check(sheetName); err != nil {
+		return err
+	}
+	return c.next.DeleteSheet(ctx, sheetName)
+}
+
+func (c *allowClient) ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error {
+	if err := c.check(sheetName); err != nil {
+		return err
+	}
+	return c.next.ExpandSheet(ctx, sheetName, rows, columns)
+}
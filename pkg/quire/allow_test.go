@@ -0,0 +1,111 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func newAllowMock() *MockSheetsClient {
+	return &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+			}, nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+		ClearFunc: func(ctx context.Context, range_ string) error {
+			return nil
+		},
+		DeleteRowsFunc: func(ctx context.Context, sheetName string, rowIndices []int) error {
+			return nil
+		},
+	}
+}
+
+func TestAllowClient_Mutations(t *testing.T) {
+	ctx := context.Background()
+	user := TestUser{ID: 1, Name: "Alice"}
+
+	mutations := map[string]func(table *Table) error{
+		"Insert":      func(table *Table) error { return table.Insert(ctx, []TestUser{user}) },
+		"Update":      func(table *Table) error { return table.Update(ctx, 0, user) },
+		"UpdateWhere": func(table *Table) error { return table.UpdateWhere(ctx, "ID", "=", 1, user) },
+		"Delete":      func(table *Table) error { return table.Delete(ctx, 0) },
+		"DeleteWhere": func(table *Table) error { return table.DeleteWhere(ctx, "ID", "=", 1) },
+		"Clear":       func(table *Table) error { return table.Clear(ctx) },
+		"Truncate":    func(table *Table) error { return table.Truncate(ctx) },
+	}
+
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			mock := newAllowMock()
+			db := &DB{client: newAllowClient(mock, []string{"Users", "Audit Log"})}
+
+			if err := mutate(db.Table("Users")); err != nil {
+				t.Errorf("%s on allowed table unexpected error = %v", name, err)
+			}
+			if err := mutate(db.Table("Audit Log")); err != nil {
+				t.Errorf("%s on allowed table with a space unexpected error = %v", name, err)
+			}
+
+			writes := len(mock.WriteCalls) + len(mock.BatchWriteCalls) + len(mock.AppendCalls) +
+				len(mock.ClearCalls) + len(mock.DeleteRowsCalls)
+			err := mutate(db.Table("Billing"))
+			if !errors.Is(err, ErrTableNotAllowed) {
+				t.Errorf("%s on other table error = %v, want ErrTableNotAllowed", name, err)
+			}
+			after := len(mock.WriteCalls) + len(mock.BatchWriteCalls) + len(mock.AppendCalls) +
+				len(mock.ClearCalls) + len(mock.DeleteRowsCalls)
+			if after != writes {
+				t.Errorf("%s on other table reached the API", name)
+			}
+		})
+	}
+}
+
+func TestAllowClient_Passthrough(t *testing.T) {
+	ctx := context.Background()
+	mock := newAllowMock()
+	client := newAllowClient(mock, []string{"Users"})
+
+	var users []TestUser
+	if err := (&DB{client: client}).Table("Billing").Query().Get(ctx, &users); err != nil {
+		t.Errorf("Get() on other table unexpected error = %v", err)
+	}
+
+	if err := client.Write(ctx, "'Users'!A2", nil); err != nil {
+		t.Errorf("Write() to quoted allowed sheet unexpected error = %v", err)
+	}
+
+	err := client.BatchWrite(ctx, map[string][][]interface{}{
+		"Users!A2":   {{1}},
+		"Billing!A2": {{2}},
+	})
+	if !errors.Is(err, ErrTableNotAllowed) || len(mock.BatchWriteCalls) != 0 {
+		t.Errorf("BatchWrite() across sheets error = %v, calls = %d, want whole batch rejected", err, len(mock.BatchWriteCalls))
+	}
+
+	if err := client.AddSheet(ctx, "Billing"); !errors.Is(err, ErrTableNotAllowed) {
+		t.Errorf("AddSheet() error = %v, want ErrTableNotAllowed", err)
+	}
+	if err := client.ExpandSheet(ctx, "Billing", 10, 10); !errors.Is(err, ErrTableNotAllowed) {
+		t.Errorf("ExpandSheet() error = %v, want ErrTableNotAllowed", err)
+	}
+	if err := client.DeleteSheet(ctx, "Billing"); !errors.Is(err, ErrTableNotAllowed) || len(mock.DeleteSheetCalls) != 0 {
+		t.Errorf("DeleteSheet() error = %v, want ErrTableNotAllowed", err)
+	}
+}
+
+func TestAllowClientImplementsInterface(t *testing.T) {
+	var _ SheetsClient = (*allowClient)(nil)
+}
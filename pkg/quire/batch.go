@@ -0,0 +1,190 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// BatchOp queues inserts, updates and deletes on one or more tables and
+// sends them together on Commit, in as few requests as the Sheets API
+// allows:
+//
+//	batch := db.Batch()
+//	batch.Insert(orders, []Order{newOrder})
+//	batch.Update(users, 3, user)
+//	batch.Delete(carts, 7)
+//	err := batch.Commit(ctx)
+//
+// Sheets has no transactions, so a batch is best effort rather than
+// atomic; see Commit.
+type BatchOp struct {
+	db      *DB
+	updates []batchUpdate
+	appends []batchAppend
+	deletes []batchDelete
+	err     error
+}
+
+type batchUpdate struct {
+	table  *Table
+	row    int
+	record interface{}
+}
+
+type batchAppend struct {
+	table *Table
+	rows  [][]interface{}
+}
+
+type batchDelete struct {
+	sheet string
+	row   int // 0-based sheet row
+}
+
+// Batch starts an empty batch of changes.
+func (db *DB) Batch() *BatchOp {
+	return &BatchOp{db: db}
+}
+
+// Insert queues records, a slice of structs, to be appended to t as
+// Table.Insert would.
+func (b *BatchOp) Insert(t *Table, records interface{}) *BatchOp {
+	values, err := t.naming().structSliceToValues(records)
+	if err != nil {
+		b.fail(fmt.Errorf("failed to convert records: %w", err))
+		return b
+	}
+	return b.Append(t, values)
+}
+
+// Append queues rows of raw cell values to be appended to t.
+func (b *BatchOp) Append(t *Table, rows [][]interface{}) *BatchOp {
+	if len(rows) > 0 {
+		b.appends = append(b.appends, batchAppend{table: t, rows: rows})
+	}
+	return b
+}
+
+// Update queues record to be written over the row at rowIndex (0-based,
+// excluding the header) of t, placing fields by header name as
+// Table.Update does.
+func (b *BatchOp) Update(t *Table, rowIndex int, record interface{}) *BatchOp {
+	if rowIndex < 0 {
+		b.fail(fmt.Errorf("row index cannot be negative"))
+		return b
+	}
+	b.updates = append(b.updates, batchUpdate{table: t, row: rowIndex, record: record})
+	return b
+}
+
+// Delete queues the removal of the row at rowIndex (0-based, excluding
+// the header) of t. The index refers to the sheet as it is before the
+// batch is committed.
+func (b *BatchOp) Delete(t *Table, rowIndex int) *BatchOp {
+	if rowIndex < 0 {
+		b.fail(fmt.Errorf("row index cannot be negative"))
+		return b
+	}
+	b.deletes = append(b.deletes, batchDelete{sheet: t.name, row: t.rowNumber(rowIndex) - 1})
+	return b
+}
+
+// fail records the first error from queueing, which Commit returns.
+func (b *BatchOp) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Len returns the number of queued operations.
+func (b *BatchOp) Len() int {
+	return len(b.updates) + len(b.appends) + len(b.deletes)
+}
+
+// Commit sends the queued changes and empties the batch. If queueing an
+// operation failed, Commit returns that error without sending anything.
+//
+// All updates are written in a single batch request, after one read of the
+// header rows they need. Inserts and appends follow, one append request per
+// table, since the API decides where appended rows go. Deletes come last,
+// one request per sheet, so the row indices given to Update and Delete
+// both refer to the sheet as it was before Commit.
+//
+// Each request is applied on its own: if one fails, Commit stops and
+// returns its error, and the changes made by earlier requests stay in
+// place. The error says which step failed.
+func (b *BatchOp) Commit(ctx context.Context) error {
+	updates, appends, deletes, err := b.updates, b.appends, b.deletes, b.err
+	b.updates, b.appends, b.deletes, b.err = nil, nil, nil, nil
+	if err != nil {
+		return err
+	}
+
+	if err := b.commitUpdates(ctx, updates); err != nil {
+		return fmt.Errorf("failed to commit updates: %w", err)
+	}
+
+	var tables []*Table
+	rows := make(map[*Table][][]interface{})
+	for _, a := range appends {
+		if _, ok := rows[a.table]; !ok {
+			tables = append(tables, a.table)
+		}
+		rows[a.table] = append(rows[a.table], a.rows...)
+	}
+	for _, t := range tables {
+		if err := t.appendValues(ctx, rows[t]); err != nil {
+			return fmt.Errorf("failed to commit inserts into %s: %w", t.name, err)
+		}
+	}
+
+	var sheets []string
+	indices := make(map[string][]int)
+	for _, d := range deletes {
+		if _, ok := indices[d.sheet]; !ok {
+			sheets = append(sheets, d.sheet)
+		}
+		indices[d.sheet] = append(indices[d.sheet], d.row)
+	}
+	for _, sheet := range sheets {
+		sort.Sort(sort.Reverse(sort.IntSlice(indices[sheet])))
+		if err := b.db.client.DeleteRows(ctx, sheet, indices[sheet]); err != nil {
+			return fmt.Errorf("failed to commit deletes from %s: %w", sheet, err)
+		}
+	}
+	return nil
+}
+
+// commitUpdates reads the header rows of the updated tables in one request
+// and writes every update in another.
+func (b *BatchOp) commitUpdates(ctx context.Context, updates []batchUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	var ranges []string
+	seen := make(map[string]bool)
+	for _, u := range updates {
+		if r := u.table.headerRange(); !seen[r] {
+			seen[r] = true
+			ranges = append(ranges, r)
+		}
+	}
+	results, err := b.db.client.BatchRead(ctx, ranges)
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	data := make(map[string][][]interface{}, len(updates))
+	for _, u := range updates {
+		headers := u.table.headerCells(results[u.table.headerRange()])
+		values, err := u.table.headerRow(u.record, headers)
+		if err != nil {
+			return fmt.Errorf("failed to convert record: %w", err)
+		}
+		first, last := cellSpan(values)
+		data[u.table.rowRange(u.row, first, last)] = [][]interface{}{values[first : last+1]}
+	}
+	return b.db.client.BatchWrite(ctx, data)
+}
@@ -0,0 +1,134 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newBatchMock() *MockSheetsClient {
+	return &MockSheetsClient{
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users!1:1":  {{"ID", "Name", "Email", "Age"}},
+				"Orders!1:1": {{"SKU", "Price", "Name"}},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+		DeleteRowsFunc: func(ctx context.Context, sheetName string, rowIndices []int) error {
+			return nil
+		},
+	}
+}
+
+func TestBatchOp_Commit(t *testing.T) {
+	mock := newBatchMock()
+	db := &DB{client: mock}
+	users, orders := db.Table("Users"), db.Table("Orders")
+
+	batch := db.Batch()
+	batch.Update(users, 0, TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 31}).
+		Update(users, 4, TestUser{ID: 5, Name: "Eve", Email: "eve@test.com", Age: 22}).
+		Update(orders, 1, TestProduct{SKU: "A-1", Name: "Anvil", Price: 99.5}).
+		Insert(users, []TestUser{{ID: 6, Name: "Frank"}}).
+		Append(users, [][]interface{}{{7, "Grace"}}).
+		Delete(users, 2).
+		Delete(orders, 0).
+		Delete(users, 7)
+	if batch.Len() != 8 {
+		t.Errorf("Len() = %d, want 8", batch.Len())
+	}
+
+	if err := batch.Commit(context.Background()); err != nil {
+		t.Fatalf("Commit() unexpected error = %v", err)
+	}
+
+	if len(mock.BatchReadCalls) != 1 || !reflect.DeepEqual(mock.BatchReadCalls[0].Ranges, []string{"Users!1:1", "Orders!1:1"}) {
+		t.Errorf("Commit() header reads = %v, want one read of both header rows", mock.BatchReadCalls)
+	}
+
+	if len(mock.BatchWriteCalls) != 1 {
+		t.Fatalf("Commit() made %d batch writes, want 1", len(mock.BatchWriteCalls))
+	}
+	wantWrites := map[string][][]interface{}{
+		"Users!A2:D2":  {{1, "Alice", "alice@test.com", 31}},
+		"Users!A6:D6":  {{5, "Eve", "eve@test.com", 22}},
+		"Orders!A3:C3": {{"A-1", 99.5, "Anvil"}},
+	}
+	if got := mock.BatchWriteCalls[0].Data; !reflect.DeepEqual(got, wantWrites) {
+		t.Errorf("Commit() wrote %v, want %v", got, wantWrites)
+	}
+
+	if len(mock.AppendCalls) != 1 {
+		t.Fatalf("Commit() made %d appends, want 1", len(mock.AppendCalls))
+	}
+	wantAppend := MockCall{Range_: "Users!A1", Values: [][]interface{}{{6, "Frank", "", 0}, {7, "Grace"}}}
+	if !reflect.DeepEqual(mock.AppendCalls[0], wantAppend) {
+		t.Errorf("Commit() appended %+v, want %+v", mock.AppendCalls[0], wantAppend)
+	}
+
+	wantDeletes := []DeleteRowsCall{
+		{SheetName: "Users", RowIndices: []int{8, 3}},
+		{SheetName: "Orders", RowIndices: []int{1}},
+	}
+	if !reflect.DeepEqual(mock.DeleteRowsCalls, wantDeletes) {
+		t.Errorf("Commit() deletes = %+v, want %+v", mock.DeleteRowsCalls, wantDeletes)
+	}
+
+	if batch.Len() != 0 {
+		t.Errorf("Len() after Commit() = %d, want 0", batch.Len())
+	}
+}
+
+func TestBatchOp_Commit_Errors(t *testing.T) {
+	ctx := context.Background()
+	mock := newBatchMock()
+	db := &DB{client: mock}
+	users := db.Table("Users")
+
+	writeErr := errors.New("write failed")
+	mock.BatchWriteFunc = func(ctx context.Context, data map[string][][]interface{}) error {
+		return writeErr
+	}
+	err := db.Batch().
+		Update(users, 0, TestUser{ID: 1}).
+		Insert(users, []TestUser{{ID: 2}}).
+		Commit(ctx)
+	if !errors.Is(err, writeErr) {
+		t.Errorf("Commit() error = %v, want %v", err, writeErr)
+	}
+	if len(mock.AppendCalls) != 0 {
+		t.Error("Commit() should stop at the failed request")
+	}
+
+	mock.Reset()
+	appendErr := errors.New("append failed")
+	mock.AppendFunc = func(ctx context.Context, range_ string, values [][]interface{}) error {
+		return appendErr
+	}
+	err = db.Batch().Insert(users, []TestUser{{ID: 2}}).Delete(users, 0).Commit(ctx)
+	if !errors.Is(err, appendErr) {
+		t.Errorf("Commit() error = %v, want %v", err, appendErr)
+	}
+	if len(mock.DeleteRowsCalls) != 0 {
+		t.Error("Commit() should not delete after a failed insert")
+	}
+
+	mock.Reset()
+	batch := db.Batch().Delete(users, 0).Insert(users, "not a slice").Update(users, -1, TestUser{})
+	if err := batch.Commit(ctx); err == nil {
+		t.Error("Commit() expected the error from queueing")
+	}
+	if len(mock.DeleteRowsCalls) != 0 || len(mock.AppendCalls) != 0 {
+		t.Error("Commit() should send nothing when queueing failed")
+	}
+	if err := batch.Commit(ctx); err != nil {
+		t.Errorf("Commit() of the emptied batch error = %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package quire
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheClient wraps a SheetsClient with a read-through cache of range
+// values. Entries expire after ttl and are dropped whenever a call writes
+// to their sheet. Sheet metadata (ListSheets) is not cached.
+type cacheClient struct {
+	next SheetsClient
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// generations counts invalidations per sheet, so a read that started
+	// before a write doesn't store its now-stale result afterwards.
+	generations map[string]uint64
+}
+
+type cacheEntry struct {
+	values  [][]interface{}
+	expires time.Time
+}
+
+func newCacheClient(next SheetsClient, ttl time.Duration) *cacheClient {
+	return &cacheClient{
+		next:        next,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]cacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+// sheetOf returns the sheet name of an A1 range such as "'My Sheet'!A1:B2".
+func sheetOf(range_ string) string {
+	name, _, _ := strings.Cut(range_, "!")
+	if len(name) >= 2 && name[0] == '\'' && name[len(name)-1] == '\'' {
+		name = strings.ReplaceAll(name[1:len(name)-1], "''", "'")
+	}
+	return name
+}
+
+// copyValues copies the outer and row slices so callers can't modify
+// cached data.
+func copyValues(values [][]interface{}) [][]interface{} {
+	if values == nil {
+		return nil
+	}
+	out := make([][]interface{}, len(values))
+	for i, row := range values {
+		out[i] = append([]interface{}(nil), row...)
+	}
+	return out
+}
+
+func (c *cacheClient) lookup(range_ string) ([][]interface{}, bool) {
+	entry, ok := c.entries[range_]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, range_)
+		return nil, false
+	}
+	return copyValues(entry.values), true
+}
+
+// store caches values for range_ unless its sheet was invalidated since
+// generation gen was observed.
+func (c *cacheClient) store(range_ string, values [][]interface{}, gen uint64) {
+	if c.generations[sheetOf(range_)] != gen {
+		return
+	}
+	c.entries[range_] = cacheEntry{values: copyValues(values), expires: c.now().Add(c.ttl)}
+}
+
+// invalidate drops every cached range of sheet.
+func (c *cacheClient) invalidate(sheet string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[sheet]++
+	for range_ := range c.entries {
+		if sheetOf(range_) == sheet {
+			delete(c.entries, range_)
+		}
+	}
+}
+
+func (c *cacheClient) Read(ctx context.Context, range_ string) ([][]interface{}, error) {
+	c.mu.Lock()
+	if values, ok := c.lookup(range_); ok {
+		c.mu.Unlock()
+		return values, nil
+	}
+	gen := c.generations[sheetOf(range_)]
+	c.mu.Unlock()
+
+	values, err := c.next.Read(ctx, range_)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.store(range_, values, gen)
+	c.mu.Unlock()
+	return values, nil
+}
+
+// BatchRead is served from the cache only if every range is cached;
+// otherwise all ranges are fetched in one call and cached.
+func (c *cacheClient) BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+	c.mu.Lock()
+	result := make(map[string][][]interface{}, len(ranges))
+	gens := make(map[string]uint64, len(ranges))
+	for _, r := range ranges {
+		values, ok := c.lookup(r)
+		if !ok {
+			result = nil
+			break
+		}
+		result[r] = values
+	}
+	if result != nil {
+		c.mu.Unlock()
+		return result, nil
+	}
+	for _, r := range ranges {
+		gens[r] = c.generations[sheetOf(r)]
+	}
+	c.mu.Unlock()
+
+	result, err := c.next.BatchRead(ctx, ranges)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	for _, r := range ranges {
+		c.store(r, result[r], gens[r])
+	}
+	c.mu.Unlock()
+	return result, nil
+}
+
+func (c *cacheClient) Write(ctx context.Context, range_ string, values [][]interface{}) error {
+	defer c.invalidate(sheetOf(range_))
+	return c.next.Write(ctx, range_, values)
+}
+
+func (c *cacheClient) BatchWrite(ctx context.Context, data map[string][][]interface{}) error {
+	defer func() {
+		for range_ := range data {
+			c.invalidate(sheetOf(range_))
+		}
+	}()
+	return c.next.BatchWrite(ctx, data)
+}
+
+func (c *cacheClient) Append(ctx context.Context, range_ string, values [][]interface{}) error {
+	defer c.invalidate(sheetOf(range_))
+	return c.next.Append(ctx, range_, values)
+}
+
+func (c *cacheClient) Clear(ctx context.Context, range_ string) error {
+	defer c.invalidate(sheetOf(range_))
+	return c.next.Clear(ctx, range_)
+}
+
+func (c *cacheClient) DeleteRows(ctx context.Context, sheetName string, rowIndices []int) error {
+	defer c.invalidate(sheetName)
+	return c.next.DeleteRows(ctx, sheetName, rowIndices)
+}
+
+func (c *cacheClient) ListSheets(ctx context.Context) ([]SheetProperties, error) {
+	return c.next.ListSheets(ctx)
+}
+
+func (c *cacheClient) AddSheet(ctx context.Context, sheetName string) error {
+	defer c.invalidate(sheetName)
+	return c.next.AddSheet(ctx, sheetName)
+}
+
+func (c *cacheClient) DeleteSheet(ctx context.Context, sheetName string) error {
+	defer c.invalidate(sheetName)
+	return c.next.DeleteSheet(ctx, sheetName)
+}
+
+func (c *cacheClient) ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error {
+	return c.next.ExpandSheet(ctx, sheetName, rows, columns)
+}
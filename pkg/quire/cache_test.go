@@ -0,0 +1,193 @@
+package quire
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCachedMock() (*MockSheetsClient, *cacheClient) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+			}, nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	return mock, newCacheClient(mock, time.Minute)
+}
+
+func TestCacheClient_SecondGetHitsCache(t *testing.T) {
+	ctx := context.Background()
+	mock, cache := newCachedMock()
+	db := &DB{client: cache}
+
+	for i := 0; i < 2; i++ {
+		var users []TestUser
+		if err := db.Table("Users").Query().Get(ctx, &users); err != nil {
+			t.Fatalf("Get() unexpected error = %v", err)
+		}
+		if len(users) != 1 {
+			t.Fatalf("Get() returned %d users, want 1", len(users))
+		}
+	}
+
+	if len(mock.ReadCalls) != 1 {
+		t.Errorf("expected 1 read, got %d", len(mock.ReadCalls))
+	}
+}
+
+func TestCacheClient_InsertInvalidates(t *testing.T) {
+	ctx := context.Background()
+	mock, cache := newCachedMock()
+	db := &DB{client: cache}
+	table := db.Table("Users")
+
+	var users []TestUser
+	if err := table.Query().Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+
+	// A write to another sheet leaves the Users entry alone.
+	if err := db.Table("Orders").Insert(ctx, []TestUser{{ID: 9}}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	if err := table.Query().Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(mock.ReadCalls) != 1 {
+		t.Fatalf("expected 1 read after unrelated insert, got %d", len(mock.ReadCalls))
+	}
+
+	if err := table.Insert(ctx, []TestUser{{ID: 2, Name: "Bob"}}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	if err := table.Query().Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(mock.ReadCalls) != 2 {
+		t.Errorf("expected a fresh read after insert, got %d reads", len(mock.ReadCalls))
+	}
+}
+
+func TestCacheClient_Expiry(t *testing.T) {
+	ctx := context.Background()
+	mock, cache := newCachedMock()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.Read(ctx, "Users")
+	now = now.Add(59 * time.Second)
+	cache.Read(ctx, "Users")
+	if len(mock.ReadCalls) != 1 {
+		t.Fatalf("expected 1 read within TTL, got %d", len(mock.ReadCalls))
+	}
+
+	now = now.Add(time.Second)
+	cache.Read(ctx, "Users")
+	if len(mock.ReadCalls) != 2 {
+		t.Errorf("expected a fresh read after TTL, got %d reads", len(mock.ReadCalls))
+	}
+}
+
+func TestCacheClient_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	_, cache := newCachedMock()
+
+	first, _ := cache.Read(ctx, "Users")
+	first[1][1] = "Mallory"
+
+	second, _ := cache.Read(ctx, "Users")
+	if second[1][1] != "Alice" {
+		t.Errorf("cached value modified through a returned slice: %v", second[1][1])
+	}
+}
+
+func TestCacheClient_BatchRead(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			result := make(map[string][][]interface{})
+			for _, r := range ranges {
+				result[r] = [][]interface{}{{r}}
+			}
+			return result, nil
+		},
+		ClearFunc: func(ctx context.Context, range_ string) error {
+			return nil
+		},
+	}
+	cache := newCacheClient(mock, time.Minute)
+
+	cache.BatchRead(ctx, []string{"Users!A:A", "'My Orders'!B:B"})
+	cache.BatchRead(ctx, []string{"Users!A:A", "'My Orders'!B:B"})
+	if len(mock.BatchReadCalls) != 1 {
+		t.Fatalf("expected 1 batch read, got %d", len(mock.BatchReadCalls))
+	}
+
+	cache.Clear(ctx, "'My Orders'!A2:Z")
+	cache.BatchRead(ctx, []string{"Users!A:A", "'My Orders'!B:B"})
+	if len(mock.BatchReadCalls) != 2 {
+		t.Errorf("expected a fresh batch read after clear, got %d", len(mock.BatchReadCalls))
+	}
+}
+
+// countingClient counts reads without the mock's unsynchronized call log.
+type countingClient struct {
+	*MockSheetsClient
+	reads atomic.Int32
+}
+
+func (c *countingClient) Read(ctx context.Context, range_ string) ([][]interface{}, error) {
+	c.reads.Add(1)
+	return [][]interface{}{{"ID"}}, nil
+}
+
+func TestCacheClient_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	counting := &countingClient{MockSheetsClient: &MockSheetsClient{}}
+	cache := newCacheClient(counting, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cache.Read(ctx, "Users")
+		}()
+		go func() {
+			defer wg.Done()
+			cache.invalidate("Users")
+		}()
+	}
+	wg.Wait()
+
+	if n := counting.reads.Load(); n < 1 || n > 20 {
+		t.Errorf("reads = %d, want between 1 and 20", n)
+	}
+}
+
+func TestSheetOf(t *testing.T) {
+	tests := map[string]string{
+		"Users":            "Users",
+		"Users!A1:B2":      "Users",
+		"'My Sheet'!A:A":   "My Sheet",
+		"'Bob''s List'!A1": "Bob's List",
+	}
+	for range_, want := range tests {
+		if got := sheetOf(range_); got != want {
+			t.Errorf("sheetOf(%q) = %q, want %q", range_, got, want)
+		}
+	}
+}
+
+func TestCacheClientInterface(t *testing.T) {
+	var _ SheetsClient = (*cacheClient)(nil)
+}
@@ -0,0 +1,51 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+)
+
+// Catalog returns the header row of every sheet in the spreadsheet, keyed
+// by sheet name, as a schema overview. The headers are read in a single
+// batch request, from row 1 of each sheet; a sheet with nothing in row 1
+// maps to an empty slice. With Config.NormalizeHeaders, the names are
+// normalized as queries would see them.
+func (db *DB) Catalog(ctx context.Context) (map[string][]string, error) {
+	names, err := db.ListTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make(map[string][]string, len(names))
+	if len(names) == 0 {
+		return catalog, nil
+	}
+
+	ranges := make([]string, len(names))
+	for i, name := range names {
+		ranges[i] = name + "!1:1"
+	}
+	results, err := db.client.BatchRead(ctx, ranges)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	for i, name := range names {
+		var headers []interface{}
+		if data := results[ranges[i]]; len(data) > 0 {
+			headers = data[0]
+		}
+		if db.normalizeHeaders {
+			headers = normalizeHeaders(headers)
+		}
+
+		columns := make([]string, len(headers))
+		for j, h := range headers {
+			if h != nil {
+				columns[j] = formatCell(h)
+			}
+		}
+		catalog[name] = columns
+	}
+	return catalog, nil
+}
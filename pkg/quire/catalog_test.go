@@ -0,0 +1,77 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDB_Catalog(t *testing.T) {
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{{Title: "Users"}, {Title: "Orders"}, {Title: "Empty"}, {Title: "Q1 Report"}}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users!1:1":     {{"ID", "Name", "Email"}},
+				"Orders!1:1":    {{"OrderID", " Total ", 2024.0, "Total"}},
+				"Q1 Report!1:1": {{"Region"}},
+			}, nil
+		},
+	}
+	ctx := context.Background()
+
+	catalog, err := (&DB{client: mock}).Catalog(ctx)
+	if err != nil {
+		t.Fatalf("Catalog() unexpected error = %v", err)
+	}
+	want := map[string][]string{
+		"Users":     {"ID", "Name", "Email"},
+		"Orders":    {"OrderID", " Total ", "2024", "Total"},
+		"Empty":     {},
+		"Q1 Report": {"Region"},
+	}
+	if !reflect.DeepEqual(catalog, want) {
+		t.Errorf("Catalog() = %v, want %v", catalog, want)
+	}
+
+	if len(mock.BatchReadCalls) != 1 {
+		t.Fatalf("Catalog() made %d batch reads, want 1", len(mock.BatchReadCalls))
+	}
+	wantRanges := []string{"Users!1:1", "Orders!1:1", "Empty!1:1", "Q1 Report!1:1"}
+	if got := mock.BatchReadCalls[0].Ranges; !reflect.DeepEqual(got, wantRanges) {
+		t.Errorf("Catalog() read %v, want %v", got, wantRanges)
+	}
+
+	normalized, err := (&DB{client: mock, normalizeHeaders: true}).Catalog(ctx)
+	if err != nil {
+		t.Fatalf("Catalog() unexpected error = %v", err)
+	}
+	if got := normalized["Orders"]; !reflect.DeepEqual(got, []string{"OrderID", "Total", "2024", "Total_2"}) {
+		t.Errorf("Catalog() with NormalizeHeaders Orders = %v", got)
+	}
+}
+
+func TestDB_Catalog_Errors(t *testing.T) {
+	ctx := context.Background()
+	listErr := errors.New("list failed")
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return nil, listErr
+		},
+	}
+	if _, err := (&DB{client: mock}).Catalog(ctx); !errors.Is(err, listErr) {
+		t.Errorf("Catalog() error = %v, want %v", err, listErr)
+	}
+
+	mock = &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return nil, nil
+		},
+	}
+	catalog, err := (&DB{client: mock}).Catalog(ctx)
+	if err != nil || len(catalog) != 0 || len(mock.BatchReadCalls) != 0 {
+		t.Errorf("Catalog() of no sheets = %v, %v, want an empty catalog without reading", catalog, err)
+	}
+}
@@ -2,51 +2,228 @@ package quire
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
+// newSheetsService is swapped out in tests to inspect the client options.
+var newSheetsService = sheets.NewService
+
 type sheetsClient struct {
-	srv           *sheets.Service
-	spreadsheetID string
+	srv              *sheets.Service
+	spreadsheetID    string
+	valueInputOption string
+
+	// valueRenderOption is how read values are rendered; empty leaves
+	// the API default, FORMATTED_VALUE.
+	valueRenderOption string
 }
 
-func newSheetsClient(credentials []byte, spreadsheetID string) (*sheetsClient, error) {
+func newSheetsClient(cfg Config) (*sheetsClient, error) {
 	ctx := context.Background()
 
-	srv, err := sheets.NewService(ctx, option.WithCredentialsJSON(credentials))
+	// Without a credential option the service uses Application Default
+	// Credentials.
+	var opts []option.ClientOption
+	switch {
+	case cfg.TokenSource != nil:
+		opts = append(opts, option.WithTokenSource(cfg.TokenSource))
+	case len(cfg.Credentials) > 0:
+		opts = append(opts, option.WithCredentialsJSON(cfg.Credentials))
+	}
+	if len(cfg.Scopes) > 0 {
+		opts = append(opts, option.WithScopes(cfg.Scopes...))
+	}
+
+	srv, err := newSheetsService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sheets service: %w", err)
 	}
 
 	return &sheetsClient{
-		srv:           srv,
-		spreadsheetID: spreadsheetID,
+		srv:               srv,
+		spreadsheetID:     cfg.SpreadsheetID,
+		valueInputOption:  cfg.ValueInputOption,
+		valueRenderOption: cfg.ValueRenderOption,
 	}, nil
 }
 
+// inputOption returns how written values are interpreted, RAW unless
+// configured otherwise.
+func (c *sheetsClient) inputOption() string {
+	if c.valueInputOption == "" {
+		return "RAW"
+	}
+	return c.valueInputOption
+}
+
+// scopeError makes a 403 caused by a token lacking write access explain
+// which scope is needed, since the API message alone doesn't say.
+func scopeError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusForbidden {
+		return err
+	}
+
+	insufficient := strings.Contains(strings.ToLower(apiErr.Message), "insufficient authentication scopes") ||
+		strings.Contains(apiErr.Error(), "ACCESS_TOKEN_SCOPE_INSUFFICIENT")
+	for _, item := range apiErr.Errors {
+		if item.Reason == "insufficientPermissions" {
+			insufficient = true
+		}
+	}
+	if !insufficient {
+		return err
+	}
+
+	return fmt.Errorf("insufficient OAuth scope: writing requires %q: %w", sheets.SpreadsheetsScope, err)
+}
+
+// apiError wraps err in an APIError for range_ if the API rejected the
+// call, and returns any other error, such as a network failure, as it is.
+func apiError(range_ string, err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	reason := ""
+	if len(apiErr.Errors) > 0 {
+		reason = apiErr.Errors[0].Reason
+	}
+	return &APIError{StatusCode: apiErr.Code, Reason: reason, Range: range_, err: err}
+}
+
+// quotaError turns a 429 response into a QuotaExceededError carrying the
+// server's retry hint, taken from the Retry-After header or, failing that,
+// the RetryInfo error detail.
+func quotaError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+		return err
+	}
+
+	retryAfter := parseRetryAfter(apiErr.Header.Get("Retry-After"))
+	if retryAfter == 0 {
+		for _, detail := range apiErr.Details {
+			info, ok := detail.(map[string]interface{})
+			if !ok || !strings.HasSuffix(fmt.Sprint(info["@type"]), "google.rpc.RetryInfo") {
+				continue
+			}
+			if d, err := time.ParseDuration(fmt.Sprint(info["retryDelay"])); err == nil && d > 0 {
+				retryAfter = d
+			}
+		}
+	}
+
+	return &QuotaExceededError{RetryAfter: retryAfter, Err: err}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date. It returns zero if the header is missing or invalid.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
 func (c *sheetsClient) Read(ctx context.Context, range_ string) ([][]interface{}, error) {
-	resp, err := c.srv.Spreadsheets.Values.Get(c.spreadsheetID, range_).Context(ctx).Do()
+	call := c.srv.Spreadsheets.Values.Get(c.spreadsheetID, range_)
+	if c.valueRenderOption != "" {
+		// Dates would otherwise come back as serial numbers.
+		call.ValueRenderOption(c.valueRenderOption).DateTimeRenderOption("FORMATTED_STRING")
+	}
+	resp, err := call.Context(ctx).Do()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read range %s: %w", range_, err)
+		return nil, fmt.Errorf("failed to read range %s: %w", range_, apiError(range_, quotaError(err)))
 	}
 	return resp.Values, nil
 }
 
+func (c *sheetsClient) BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+	call := c.srv.Spreadsheets.Values.BatchGet(c.spreadsheetID).Ranges(ranges...)
+	if c.valueRenderOption != "" {
+		call.ValueRenderOption(c.valueRenderOption).DateTimeRenderOption("FORMATTED_STRING")
+	}
+	resp, err := call.Context(ctx).Do()
+	if err != nil {
+		joined := strings.Join(ranges, ", ")
+		return nil, fmt.Errorf("failed to read ranges %s: %w", joined, apiError(joined, quotaError(err)))
+	}
+
+	// Ranges come back normalized (e.g. "Users!A1:A100"), so key the
+	// result by the requested range using response order.
+	result := make(map[string][][]interface{}, len(ranges))
+	for i, vr := range resp.ValueRanges {
+		if i < len(ranges) {
+			result[ranges[i]] = vr.Values
+		}
+	}
+	return result, nil
+}
+
 func (c *sheetsClient) Write(ctx context.Context, range_ string, values [][]interface{}) error {
 	valueRange := &sheets.ValueRange{
 		Values: values,
 	}
 
 	_, err := c.srv.Spreadsheets.Values.Update(c.spreadsheetID, range_, valueRange).
-		ValueInputOption("RAW").
+		ValueInputOption(c.inputOption()).
 		Context(ctx).
 		Do()
 
 	if err != nil {
-		return fmt.Errorf("failed to write to range %s: %w", range_, err)
+		return fmt.Errorf("failed to write to range %s: %w", range_, scopeError(apiError(range_, quotaError(err))))
+	}
+	return nil
+}
+
+// BatchWrite writes several ranges, keyed by A1 range, in one request.
+func (c *sheetsClient) BatchWrite(ctx context.Context, data map[string][][]interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	ranges := make([]string, 0, len(data))
+	for range_ := range data {
+		ranges = append(ranges, range_)
+	}
+	sort.Strings(ranges)
+
+	valueRanges := make([]*sheets.ValueRange, len(ranges))
+	for i, range_ := range ranges {
+		valueRanges[i] = &sheets.ValueRange{Range: range_, Values: data[range_]}
+	}
+
+	_, err := c.srv.Spreadsheets.Values.BatchUpdate(c.spreadsheetID, &sheets.BatchUpdateValuesRequest{
+		ValueInputOption: c.inputOption(),
+		Data:             valueRanges,
+	}).Context(ctx).Do()
+
+	if err != nil {
+		joined := strings.Join(ranges, ", ")
+		return fmt.Errorf("failed to write ranges %s: %w", joined, scopeError(apiError(joined, quotaError(err))))
 	}
 	return nil
 }
@@ -57,13 +234,13 @@ func (c *sheetsClient) Append(ctx context.Context, range_ string, values [][]int
 	}
 
 	_, err := c.srv.Spreadsheets.Values.Append(c.spreadsheetID, range_, valueRange).
-		ValueInputOption("RAW").
+		ValueInputOption(c.inputOption()).
 		InsertDataOption("INSERT_ROWS").
 		Context(ctx).
 		Do()
 
 	if err != nil {
-		return fmt.Errorf("failed to append to range %s: %w", range_, err)
+		return fmt.Errorf("failed to append to range %s: %w", range_, scopeError(apiError(range_, quotaError(err))))
 	}
 	return nil
 }
@@ -74,7 +251,7 @@ func (c *sheetsClient) Clear(ctx context.Context, range_ string) error {
 		Do()
 
 	if err != nil {
-		return fmt.Errorf("failed to clear range %s: %w", range_, err)
+		return fmt.Errorf("failed to clear range %s: %w", range_, scopeError(apiError(range_, quotaError(err))))
 	}
 	return nil
 }
@@ -89,8 +266,17 @@ func (c *sheetsClient) DeleteRows(ctx context.Context, sheetName string, rowIndi
 		return fmt.Errorf("failed to get sheet ID: %w", err)
 	}
 
+	// The requests in a batch are applied in order, each against the sheet
+	// as the previous ones left it. Deleting from the bottom up keeps the
+	// remaining indices pointing at the rows originally asked for.
+	indices := append([]int(nil), rowIndices...)
+	sort.Sort(sort.Reverse(sort.IntSlice(indices)))
+
 	var requests []*sheets.Request
-	for _, idx := range rowIndices {
+	for i, idx := range indices {
+		if i > 0 && idx == indices[i-1] {
+			continue
+		}
 		requests = append(requests, &sheets.Request{
 			DeleteDimension: &sheets.DeleteDimensionRequest{
 				Range: &sheets.DimensionRange{
@@ -108,23 +294,124 @@ func (c *sheetsClient) DeleteRows(ctx context.Context, sheetName string, rowIndi
 	}).Context(ctx).Do()
 
 	if err != nil {
-		return fmt.Errorf("failed to delete rows: %w", err)
+		return fmt.Errorf("failed to delete rows: %w", scopeError(apiError(sheetName, quotaError(err))))
 	}
 
 	return nil
 }
 
-func (c *sheetsClient) getSheetID(ctx context.Context, sheetName string) (int64, error) {
-	spreadsheet, err := c.srv.Spreadsheets.Get(c.spreadsheetID).Context(ctx).Do()
+func (c *sheetsClient) ListSheets(ctx context.Context) ([]SheetProperties, error) {
+	spreadsheet, err := c.srv.Spreadsheets.Get(c.spreadsheetID).
+		Fields("sheets.properties").
+		Context(ctx).
+		Do()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get spreadsheet: %w", err)
+		return nil, fmt.Errorf("failed to get spreadsheet: %w", apiError("", quotaError(err)))
 	}
 
+	result := make([]SheetProperties, 0, len(spreadsheet.Sheets))
 	for _, sheet := range spreadsheet.Sheets {
-		if sheet.Properties.Title == sheetName {
-			return sheet.Properties.SheetId, nil
+		props := SheetProperties{
+			SheetID: sheet.Properties.SheetId,
+			Title:   sheet.Properties.Title,
+		}
+		if grid := sheet.Properties.GridProperties; grid != nil {
+			props.RowCount = int(grid.RowCount)
+			props.ColumnCount = int(grid.ColumnCount)
+		}
+		result = append(result, props)
+	}
+	return result, nil
+}
+
+func (c *sheetsClient) ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error {
+	if rows <= 0 && columns <= 0 {
+		return nil
+	}
+
+	sheetID, err := c.getSheetID(ctx, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	var requests []*sheets.Request
+	if rows > 0 {
+		requests = append(requests, &sheets.Request{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:   sheetID,
+				Dimension: "ROWS",
+				Length:    int64(rows),
+			},
+		})
+	}
+	if columns > 0 {
+		requests = append(requests, &sheets.Request{
+			AppendDimension: &sheets.AppendDimensionRequest{
+				SheetId:   sheetID,
+				Dimension: "COLUMNS",
+				Length:    int64(columns),
+			},
+		})
+	}
+
+	_, err = c.srv.Spreadsheets.BatchUpdate(c.spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}).Context(ctx).Do()
+
+	if err != nil {
+		return fmt.Errorf("failed to expand sheet %s: %w", sheetName, scopeError(apiError(sheetName, quotaError(err))))
+	}
+
+	return nil
+}
+
+// AddSheet adds an empty sheet (tab) named sheetName to the spreadsheet.
+func (c *sheetsClient) AddSheet(ctx context.Context, sheetName string) error {
+	_, err := c.srv.Spreadsheets.BatchUpdate(c.spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{
+				Properties: &sheets.SheetProperties{Title: sheetName},
+			},
+		}},
+	}).Context(ctx).Do()
+
+	if err != nil {
+		return fmt.Errorf("failed to add sheet %s: %w", sheetName, scopeError(apiError(sheetName, quotaError(err))))
+	}
+	return nil
+}
+
+// DeleteSheet removes the sheet (tab) named sheetName, and all its data,
+// from the spreadsheet.
+func (c *sheetsClient) DeleteSheet(ctx context.Context, sheetName string) error {
+	sheetID, err := c.getSheetID(ctx, sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+
+	_, err = c.srv.Spreadsheets.BatchUpdate(c.spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			DeleteSheet: &sheets.DeleteSheetRequest{SheetId: sheetID},
+		}},
+	}).Context(ctx).Do()
+
+	if err != nil {
+		return fmt.Errorf("failed to delete sheet %s: %w", sheetName, scopeError(apiError(sheetName, quotaError(err))))
+	}
+	return nil
+}
+
+func (c *sheetsClient) getSheetID(ctx context.Context, sheetName string) (int64, error) {
+	sheetList, err := c.ListSheets(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sheet := range sheetList {
+		if sheet.Title == sheetName {
+			return sheet.SheetID, nil
 		}
 	}
 
-	return 0, fmt.Errorf("sheet %q not found", sheetName)
+	return 0, fmt.Errorf("%w: %q", ErrSheetNotFound, sheetName)
 }
@@ -0,0 +1,618 @@
+package quire
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// newTestSheetsClient returns a real sheetsClient talking to a fake
+// Sheets API served by handler.
+func newTestSheetsClient(t *testing.T, handler http.HandlerFunc) *sheetsClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	service, err := sheets.NewService(context.Background(),
+		option.WithEndpoint(srv.URL),
+		option.WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create sheets service: %v", err)
+	}
+
+	return &sheetsClient{srv: service, spreadsheetID: "test-id"}
+}
+
+func TestNewSheetsClient_Scopes(t *testing.T) {
+	original := newSheetsService
+	defer func() { newSheetsService = original }()
+
+	var captured []option.ClientOption
+	newSheetsService = func(ctx context.Context, opts ...option.ClientOption) (*sheets.Service, error) {
+		captured = opts
+		return &sheets.Service{}, nil
+	}
+
+	_, err := newSheetsClient(Config{
+		SpreadsheetID: "test-id",
+		Credentials:   []byte(`{"type":"service_account"}`),
+		Scopes:        []string{sheets.SpreadsheetsReadonlyScope},
+	})
+	if err != nil {
+		t.Fatalf("newSheetsClient() unexpected error = %v", err)
+	}
+
+	want := option.WithScopes(sheets.SpreadsheetsReadonlyScope)
+	found := false
+	for _, opt := range captured {
+		if reflect.DeepEqual(opt, want) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("newSheetsClient() options %v do not include scopes", captured)
+	}
+}
+
+func TestNewSheetsClient_TokenSource(t *testing.T) {
+	original := newSheetsService
+	defer func() { newSheetsService = original }()
+
+	var captured []option.ClientOption
+	newSheetsService = func(ctx context.Context, opts ...option.ClientOption) (*sheets.Service, error) {
+		captured = opts
+		return &sheets.Service{}, nil
+	}
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})
+	if _, err := newSheetsClient(Config{SpreadsheetID: "test-id", TokenSource: ts}); err != nil {
+		t.Fatalf("newSheetsClient() unexpected error = %v", err)
+	}
+
+	want := []option.ClientOption{option.WithTokenSource(ts)}
+	if !reflect.DeepEqual(captured, want) {
+		t.Errorf("newSheetsClient() options = %v, want only the token source", captured)
+	}
+}
+
+func TestNewSheetsClient_DefaultCredentials(t *testing.T) {
+	original := newSheetsService
+	defer func() { newSheetsService = original }()
+
+	var captured []option.ClientOption
+	newSheetsService = func(ctx context.Context, opts ...option.ClientOption) (*sheets.Service, error) {
+		captured = opts
+		return &sheets.Service{}, nil
+	}
+
+	if _, err := newSheetsClient(Config{SpreadsheetID: "test-id", UseDefaultCredentials: true}); err != nil {
+		t.Fatalf("newSheetsClient() unexpected error = %v", err)
+	}
+	if len(captured) != 0 {
+		t.Errorf("newSheetsClient() options = %v, want none so ADC is used", captured)
+	}
+
+	// Explicit credentials take precedence over default credentials.
+	creds := []byte(`{"type":"service_account"}`)
+	if _, err := newSheetsClient(Config{SpreadsheetID: "test-id", Credentials: creds, UseDefaultCredentials: true}); err != nil {
+		t.Fatalf("newSheetsClient() unexpected error = %v", err)
+	}
+	want := []option.ClientOption{option.WithCredentialsJSON(creds)}
+	if !reflect.DeepEqual(captured, want) {
+		t.Errorf("newSheetsClient() options = %v, want the credentials JSON", captured)
+	}
+}
+
+func TestScopeError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantScope bool
+	}{
+		{
+			name: "insufficient scopes message",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "Request had insufficient authentication scopes.",
+			},
+			wantScope: true,
+		},
+		{
+			name: "insufficient permissions reason",
+			err: &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: "insufficientPermissions"}},
+			},
+			wantScope: true,
+		},
+		{
+			name: "other forbidden",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: "The caller does not have permission",
+			},
+			wantScope: false,
+		},
+		{
+			name:      "not found",
+			err:       &googleapi.Error{Code: http.StatusNotFound},
+			wantScope: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scopeError(tt.err)
+			gotScope := strings.Contains(err.Error(), sheets.SpreadsheetsScope)
+			if gotScope != tt.wantScope {
+				t.Errorf("scopeError() = %v, mentions scope %v, want %v", err, gotScope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestSheetsClient_WriteScopeError(t *testing.T) {
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":403,"message":"Request had insufficient authentication scopes.","status":"PERMISSION_DENIED"}}`))
+	})
+
+	err := client.Write(context.Background(), "Users!A2:B2", [][]interface{}{{1, "Alice"}})
+	if err == nil {
+		t.Fatal("Write() expected error")
+	}
+	if !strings.Contains(err.Error(), sheets.SpreadsheetsScope) {
+		t.Errorf("Write() error = %v, want mention of %s", err, sheets.SpreadsheetsScope)
+	}
+}
+
+func TestSheetsClient_QuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		body       string
+		retryAfter time.Duration
+	}{
+		{
+			name:       "Retry-After header",
+			header:     "7",
+			body:       `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`,
+			retryAfter: 7 * time.Second,
+		},
+		{
+			name:       "RetryInfo detail",
+			body:       `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED","details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"12s"}]}}`,
+			retryAfter: 12 * time.Second,
+		},
+		{
+			name: "no hint",
+			body: `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Read(context.Background(), "Users")
+
+			var quotaErr *QuotaExceededError
+			if !errors.As(err, &quotaErr) {
+				t.Fatalf("Read() error = %v, want QuotaExceededError", err)
+			}
+			if quotaErr.RetryAfter != tt.retryAfter {
+				t.Errorf("RetryAfter = %v, want %v", quotaErr.RetryAfter, tt.retryAfter)
+			}
+			if !isRetryable(err) {
+				t.Error("QuotaExceededError should be retryable")
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("30"); d != 30*time.Second {
+		t.Errorf("parseRetryAfter(30) = %v", d)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(date); d <= 0 || d > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %v, want within a minute", date, d)
+	}
+	for _, v := range []string{"", "soon", "-5"} {
+		if d := parseRetryAfter(v); d != 0 {
+			t.Errorf("parseRetryAfter(%q) = %v, want 0", v, d)
+		}
+	}
+}
+
+func TestSheetsClient_ListSheets(t *testing.T) {
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sheets":[
+			{"properties":{"sheetId":0,"title":"Users","gridProperties":{"rowCount":1000,"columnCount":26}}},
+			{"properties":{"sheetId":42,"title":"Orders","gridProperties":{"rowCount":50,"columnCount":5}}}
+		]}`))
+	})
+
+	got, err := client.ListSheets(context.Background())
+	if err != nil {
+		t.Fatalf("ListSheets() unexpected error = %v", err)
+	}
+
+	want := []SheetProperties{
+		{SheetID: 0, Title: "Users", RowCount: 1000, ColumnCount: 26},
+		{SheetID: 42, Title: "Orders", RowCount: 50, ColumnCount: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSheets() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSheetsClient_ExpandSheet(t *testing.T) {
+	var body string
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"sheets":[{"properties":{"sheetId":7,"title":"Users"}}]}`))
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte(`{}`))
+	})
+
+	if err := client.ExpandSheet(context.Background(), "Users", 10, 2); err != nil {
+		t.Fatalf("ExpandSheet() unexpected error = %v", err)
+	}
+
+	for _, want := range []string{`"sheetId":7`, `"dimension":"ROWS","length":10`, `"dimension":"COLUMNS","length":2`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("ExpandSheet() request %s missing %s", body, want)
+		}
+	}
+}
+
+func TestSheetsClient_DeleteRows_NonContiguous(t *testing.T) {
+	var req sheets.BatchUpdateSpreadsheetRequest
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"sheets":[{"properties":{"sheetId":7,"title":"Users"}}]}`))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	if err := client.DeleteRows(context.Background(), "Users", []int{1, 3, 5, 3}); err != nil {
+		t.Fatalf("DeleteRows() unexpected error = %v", err)
+	}
+
+	// Apply the requests one after another, as the API does.
+	rows := []string{"header", "a", "b", "c", "d", "e", "f"}
+	for _, r := range req.Requests {
+		start, end := r.DeleteDimension.Range.StartIndex, r.DeleteDimension.Range.EndIndex
+		rows = append(rows[:start], rows[end:]...)
+	}
+
+	want := []string{"header", "b", "d", "f"}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows after DeleteRows() = %v, want %v", rows, want)
+	}
+}
+
+func TestSheetsClient_SheetNotFound(t *testing.T) {
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s request for a missing sheet", r.Method)
+		}
+		w.Write([]byte(`{"sheets":[{"properties":{"sheetId":7,"title":"Users"}}]}`))
+	})
+	ctx := context.Background()
+
+	if err := client.DeleteRows(ctx, "Orders", []int{1}); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("DeleteRows() error = %v, want ErrSheetNotFound", err)
+	}
+	if err := client.ExpandSheet(ctx, "Orders", 10, 0); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ExpandSheet() error = %v, want ErrSheetNotFound", err)
+	}
+	if err := client.DeleteSheet(ctx, "Orders"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("DeleteSheet() error = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestSheetsClient_BatchWrite(t *testing.T) {
+	var req sheets.BatchUpdateValuesRequest
+	var path string
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+
+	err := client.BatchWrite(context.Background(), map[string][][]interface{}{
+		"Users!A3:B3": {{"b", 2.0}},
+		"Users!A2:B2": {{"a", 1.0}},
+	})
+	if err != nil {
+		t.Fatalf("BatchWrite() unexpected error = %v", err)
+	}
+
+	if !strings.HasSuffix(path, "/values:batchUpdate") {
+		t.Errorf("BatchWrite() called %s, want values:batchUpdate", path)
+	}
+	if req.ValueInputOption != "RAW" || len(req.Data) != 2 {
+		t.Fatalf("BatchWrite() request = %+v, want 2 RAW ranges", req)
+	}
+	if req.Data[0].Range != "Users!A2:B2" || req.Data[1].Range != "Users!A3:B3" {
+		t.Errorf("BatchWrite() ranges = %s, %s, want sorted", req.Data[0].Range, req.Data[1].Range)
+	}
+}
+
+func TestSheetsClient_AddSheet(t *testing.T) {
+	var req sheets.BatchUpdateSpreadsheetRequest
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+
+	if err := client.AddSheet(context.Background(), "Orders"); err != nil {
+		t.Fatalf("AddSheet() unexpected error = %v", err)
+	}
+	if len(req.Requests) != 1 || req.Requests[0].AddSheet == nil ||
+		req.Requests[0].AddSheet.Properties.Title != "Orders" {
+		t.Errorf("AddSheet() request = %+v, want one addSheet for Orders", req.Requests)
+	}
+}
+
+func TestSheetsClient_DeleteSheet(t *testing.T) {
+	var req sheets.BatchUpdateSpreadsheetRequest
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"sheets":[{"properties":{"sheetId":7,"title":"Users"}},{"properties":{"sheetId":9,"title":"Orders"}}]}`))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	if err := client.DeleteSheet(context.Background(), "Orders"); err != nil {
+		t.Fatalf("DeleteSheet() unexpected error = %v", err)
+	}
+	if len(req.Requests) != 1 || req.Requests[0].DeleteSheet == nil ||
+		req.Requests[0].DeleteSheet.SheetId != 9 {
+		t.Errorf("DeleteSheet() request = %+v, want one deleteSheet for sheet 9", req.Requests)
+	}
+}
+
+func TestSheetsClient_ValueInputOption(t *testing.T) {
+	ctx := context.Background()
+
+	for _, option := range []string{"", "RAW", "USER_ENTERED"} {
+		var got []string
+		client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, r.URL.Query().Get("valueInputOption"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		})
+		client.valueInputOption = option
+
+		if err := client.Write(ctx, "Users!A2", [][]interface{}{{"=1+1"}}); err != nil {
+			t.Fatalf("Write() unexpected error = %v", err)
+		}
+		if err := client.Append(ctx, "Users!A1", [][]interface{}{{"2024-01-31"}}); err != nil {
+			t.Fatalf("Append() unexpected error = %v", err)
+		}
+
+		want := option
+		if want == "" {
+			want = "RAW"
+		}
+		if !reflect.DeepEqual(got, []string{want, want}) {
+			t.Errorf("option %q: requests used %v, want %s", option, got, want)
+		}
+	}
+}
+
+func TestSheetsClient_ValueRenderOption(t *testing.T) {
+	ctx := context.Background()
+
+	type params struct{ render, dateTime string }
+	for _, option := range []string{"", "FORMULA"} {
+		var got []params
+		client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			got = append(got, params{q.Get("valueRenderOption"), q.Get("dateTimeRenderOption")})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		})
+		client.valueRenderOption = option
+
+		if _, err := client.Read(ctx, "Users"); err != nil {
+			t.Fatalf("Read() unexpected error = %v", err)
+		}
+		if _, err := client.BatchRead(ctx, []string{"Users"}); err != nil {
+			t.Fatalf("BatchRead() unexpected error = %v", err)
+		}
+
+		want := params{}
+		if option != "" {
+			want = params{option, "FORMATTED_STRING"}
+		}
+		if !reflect.DeepEqual(got, []params{want, want}) {
+			t.Errorf("option %q: requests used %+v, want %+v", option, got, want)
+		}
+	}
+}
+
+func TestNewSheetsClient_ValueInputOption(t *testing.T) {
+	original := newSheetsService
+	defer func() { newSheetsService = original }()
+	newSheetsService = func(ctx context.Context, opts ...option.ClientOption) (*sheets.Service, error) {
+		return &sheets.Service{}, nil
+	}
+
+	client, err := newSheetsClient(Config{
+		SpreadsheetID:    "test-id",
+		Credentials:      []byte(`{"type":"service_account"}`),
+		ValueInputOption: "USER_ENTERED",
+	})
+	if err != nil {
+		t.Fatalf("newSheetsClient() unexpected error = %v", err)
+	}
+	if client.inputOption() != "USER_ENTERED" {
+		t.Errorf("inputOption() = %q, want USER_ENTERED", client.inputOption())
+	}
+}
+
+func TestSheetsClientImplementsInterface(t *testing.T) {
+	var _ SheetsClient = (*sheetsClient)(nil)
+}
+
+func TestAPIError_Classification(t *testing.T) {
+	tests := []struct {
+		name                  string
+		err                   error
+		wantStatus            int
+		wantReason            string
+		rateLimited, notFound bool
+		permissionDenied      bool
+	}{
+		{
+			name:        "too many requests",
+			err:         &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Quota exceeded"},
+			wantStatus:  http.StatusTooManyRequests,
+			rateLimited: true,
+		},
+		{
+			name: "rate limit reported as forbidden",
+			err: &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}},
+			},
+			wantStatus:  http.StatusForbidden,
+			wantReason:  "userRateLimitExceeded",
+			rateLimited: true,
+		},
+		{
+			name:       "not found",
+			err:        &googleapi.Error{Code: http.StatusNotFound, Errors: []googleapi.ErrorItem{{Reason: "notFound"}}},
+			wantStatus: http.StatusNotFound,
+			wantReason: "notFound",
+			notFound:   true,
+		},
+		{
+			name:             "permission denied",
+			err:              &googleapi.Error{Code: http.StatusForbidden, Message: "The caller does not have permission"},
+			wantStatus:       http.StatusForbidden,
+			permissionDenied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := apiError("Users!A1:B2", quotaError(tt.err))
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("apiError() = %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus || apiErr.Reason != tt.wantReason || apiErr.Range != "Users!A1:B2" {
+				t.Errorf("APIError = %+v, want status %d, reason %q", apiErr, tt.wantStatus, tt.wantReason)
+			}
+			if apiErr.IsRateLimited() != tt.rateLimited {
+				t.Errorf("IsRateLimited() = %v, want %v", apiErr.IsRateLimited(), tt.rateLimited)
+			}
+			if apiErr.IsNotFound() != tt.notFound {
+				t.Errorf("IsNotFound() = %v, want %v", apiErr.IsNotFound(), tt.notFound)
+			}
+			if apiErr.IsPermissionDenied() != tt.permissionDenied {
+				t.Errorf("IsPermissionDenied() = %v, want %v", apiErr.IsPermissionDenied(), tt.permissionDenied)
+			}
+
+			var gErr *googleapi.Error
+			if !errors.As(err, &gErr) || gErr != tt.err {
+				t.Errorf("APIError should wrap the googleapi.Error, got %v", err)
+			}
+		})
+	}
+
+	plain := errors.New("connection reset")
+	if err := apiError("Users", plain); err != plain {
+		t.Errorf("apiError() = %v, want non-API errors returned as they are", err)
+	}
+}
+
+func TestSheetsClient_APIError(t *testing.T) {
+	client := newTestSheetsClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","errors":[{"reason":"notFound"}],"status":"NOT_FOUND"}}`))
+	})
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"Read": func() error {
+			_, err := client.Read(ctx, "Users!A1:B2")
+			return err
+		},
+		"Write": func() error {
+			return client.Write(ctx, "Users!A1:B2", [][]interface{}{{1, 2}})
+		},
+		"Append": func() error {
+			return client.Append(ctx, "Users!A1:B2", [][]interface{}{{1, 2}})
+		},
+		"Clear": func() error {
+			return client.Clear(ctx, "Users!A1:B2")
+		},
+	}
+	for name, call := range calls {
+		var apiErr *APIError
+		if err := call(); !errors.As(err, &apiErr) {
+			t.Errorf("%s() error = %v, want an APIError", name, err)
+			continue
+		}
+		if !apiErr.IsNotFound() || apiErr.Reason != "notFound" || apiErr.Range != "Users!A1:B2" {
+			t.Errorf("%s() APIError = %+v, want a not found error for Users!A1:B2", name, apiErr)
+		}
+	}
+
+	var apiErr *APIError
+	if err := client.DeleteRows(ctx, "Users", []int{1}); !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+		t.Errorf("DeleteRows() error = %v, want a not found APIError", err)
+	}
+}
@@ -0,0 +1,123 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// UpsertIncrement adds delta to counterColumn in the row whose keyColumns
+// all equal the given values, e.g. the hit count for a day and page, and
+// returns the counter's new value. If no row matches, it appends one with
+// the key values and delta as the counter. A blank counter counts as 0; a
+// non-numeric one is an error. If several rows match, the first is
+// incremented.
+//
+//	hits, err := table.UpsertIncrement(ctx, map[string]interface{}{
+//		"Date": "2024-06-01",
+//		"Page": "/home",
+//	}, "Hits", 1)
+//
+// The sheet is read and then written in separate requests, so the
+// increment is not atomic on the server: another client changing the row in
+// between loses an update, or inserts a duplicate row. Calls through the
+// same DB are serialized per sheet, which makes concurrent increments from
+// one process safe; writers in other processes still race.
+func (t *Table) UpsertIncrement(ctx context.Context, keyColumns map[string]interface{}, counterColumn string, delta float64) (float64, error) {
+	if len(keyColumns) == 0 {
+		return 0, fmt.Errorf("at least one key column is required")
+	}
+
+	unlock := t.db.lockSheet(t.name)
+	defer unlock()
+
+	data, err := t.readData(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read data: %w", err)
+	}
+	if len(data) == 0 {
+		return 0, fmt.Errorf("column %q not found", counterColumn)
+	}
+
+	headers := data[0]
+	counter := findColumnLenient(headers, counterColumn, t.db.lenientHeaders)
+	if counter == -1 {
+		return 0, fmt.Errorf("column %q not found", counterColumn)
+	}
+
+	filters := make([]Filter, 0, len(keyColumns))
+	for name, value := range keyColumns {
+		if findColumnLenient(headers, name, t.db.lenientHeaders) == -1 {
+			return 0, fmt.Errorf("column %q not found", name)
+		}
+		filters = append(filters, Filter{Column: name, Operator: "=", Value: value, lenient: t.db.lenientHeaders})
+	}
+
+	for i, row := range data[1:] {
+		if !matchesAll(row, headers, filters) {
+			continue
+		}
+
+		current, err := counterValue(row, counter)
+		if err != nil {
+			return 0, fmt.Errorf("row %d: %w", i, err)
+		}
+		total := current + delta
+		if err := t.db.client.Write(ctx, t.rowRange(i, counter, counter), [][]interface{}{{total}}); err != nil {
+			return 0, fmt.Errorf("failed to update row %d: %w", i, err)
+		}
+		return total, nil
+	}
+
+	values, err := t.fieldsRow(headers, keyColumns)
+	if err != nil {
+		return 0, err
+	}
+	values[counter] = delta
+	_, last := cellSpan(values)
+	if err := t.db.client.Append(ctx, t.appendRange(), [][]interface{}{values[:last+1]}); err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
+
+// matchesAll reports whether row satisfies every filter.
+func matchesAll(row []interface{}, headers []interface{}, filters []Filter) bool {
+	for _, f := range filters {
+		if !matchesFilter(row, headers, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// counterValue parses the number in row's cell at col, treating a blank
+// or missing cell as 0.
+func counterValue(row []interface{}, col int) (float64, error) {
+	if col >= len(row) || row[col] == nil {
+		return 0, nil
+	}
+	if f, ok := row[col].(float64); ok {
+		return f, nil
+	}
+
+	s := strings.TrimSpace(formatCell(row[col]))
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("counter %q is not a number", s)
+	}
+	return f, nil
+}
+
+// lockSheet locks the DB's mutex for the named sheet and returns the
+// function that unlocks it.
+func (db *DB) lockSheet(name string) (unlock func()) {
+	mu, _ := db.locks.LoadOrStore(name, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
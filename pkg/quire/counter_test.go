@@ -0,0 +1,153 @@
+package quire
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTable_UpsertIncrement(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Date", "Page", "Hits"},
+				{"2024-06-01", "/home", 41.0},
+				{"2024-06-01", "/about", 3.0},
+				{"2024-06-02", "/home", nil},
+				{"2024-06-03", "/home", "7"},
+			}, nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Hits")
+
+	tests := []struct {
+		date, page string
+		delta      float64
+		want       float64
+		wantRange  string
+	}{
+		{"2024-06-01", "/home", 1, 42, "Hits!C2:C2"},
+		{"2024-06-01", "/about", -1, 2, "Hits!C3:C3"},
+		{"2024-06-02", "/home", 5, 5, "Hits!C4:C4"},
+		{"2024-06-03", "/home", 0.5, 7.5, "Hits!C5:C5"},
+	}
+	for _, tt := range tests {
+		mock.Reset()
+		keys := map[string]interface{}{"Date": tt.date, "Page": tt.page}
+		got, err := table.UpsertIncrement(ctx, keys, "Hits", tt.delta)
+		if err != nil {
+			t.Fatalf("UpsertIncrement(%s %s) unexpected error = %v", tt.date, tt.page, err)
+		}
+		if got != tt.want {
+			t.Errorf("UpsertIncrement(%s %s) = %v, want %v", tt.date, tt.page, got, tt.want)
+		}
+		if len(mock.AppendCalls) != 0 || len(mock.WriteCalls) != 1 {
+			t.Fatalf("UpsertIncrement(%s %s) made %d writes and %d appends, want 1 write",
+				tt.date, tt.page, len(mock.WriteCalls), len(mock.AppendCalls))
+		}
+		call := mock.WriteCalls[0]
+		if call.Range_ != tt.wantRange || !reflect.DeepEqual(call.Values, [][]interface{}{{tt.want}}) {
+			t.Errorf("UpsertIncrement(%s %s) wrote %v to %s, want %v to %s",
+				tt.date, tt.page, call.Values, call.Range_, tt.want, tt.wantRange)
+		}
+	}
+}
+
+func TestTable_UpsertIncrement_InsertsNewRow(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Date", "Hits", "Page", "Note"},
+				{"2024-06-01", 41.0, "/home"},
+			}, nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Hits")
+
+	keys := map[string]interface{}{"Date": "2024-06-02", "Page": "/home"}
+	got, err := table.UpsertIncrement(context.Background(), keys, "Hits", 1)
+	if err != nil {
+		t.Fatalf("UpsertIncrement() unexpected error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("UpsertIncrement() = %v, want 1", got)
+	}
+
+	if len(mock.WriteCalls) != 0 || len(mock.AppendCalls) != 1 {
+		t.Fatalf("UpsertIncrement() made %d writes and %d appends, want 1 append",
+			len(mock.WriteCalls), len(mock.AppendCalls))
+	}
+	want := [][]interface{}{{"2024-06-02", 1.0, "/home"}}
+	if got := mock.AppendCalls[0].Values; !reflect.DeepEqual(got, want) {
+		t.Errorf("UpsertIncrement() appended %v, want %v", got, want)
+	}
+}
+
+func TestTable_UpsertIncrement_LenientHeaders(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{" Day ", "PAGE", "Hits"},
+				{"2024-06-01", "/home", 41.0},
+			}, nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock, lenientHeaders: true}).Table("Hits")
+
+	keys := map[string]interface{}{"Day": "2024-06-01", "Page": "/home"}
+	got, err := table.UpsertIncrement(context.Background(), keys, "hits", 1)
+	if err != nil {
+		t.Fatalf("UpsertIncrement() unexpected error = %v", err)
+	}
+	if got != 42 {
+		t.Errorf("UpsertIncrement() = %v, want 42", got)
+	}
+	if len(mock.WriteCalls) != 1 || len(mock.AppendCalls) != 0 {
+		t.Fatalf("UpsertIncrement() made %d writes and %d appends, want 1 write",
+			len(mock.WriteCalls), len(mock.AppendCalls))
+	}
+	if got := mock.WriteCalls[0].Range_; got != "Hits!C2:C2" {
+		t.Errorf("UpsertIncrement() wrote to %s, want Hits!C2:C2", got)
+	}
+}
+
+func TestTable_UpsertIncrement_Errors(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Date", "Hits"},
+				{"2024-06-01", "many"},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Hits")
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		keys    map[string]interface{}
+		counter string
+	}{
+		"no keys":         {nil, "Hits"},
+		"unknown key":     {map[string]interface{}{"Day": "2024-06-01"}, "Hits"},
+		"unknown counter": {map[string]interface{}{"Date": "2024-06-01"}, "Views"},
+		"non-numeric":     {map[string]interface{}{"Date": "2024-06-01"}, "Hits"},
+	}
+	for name, tt := range tests {
+		if _, err := table.UpsertIncrement(ctx, tt.keys, tt.counter, 1); err == nil {
+			t.Errorf("%s: UpsertIncrement() expected error", name)
+		}
+	}
+	if len(mock.WriteCalls) != 0 || len(mock.AppendCalls) != 0 {
+		t.Errorf("UpsertIncrement() wrote despite errors: %v %v", mock.WriteCalls, mock.AppendCalls)
+	}
+}
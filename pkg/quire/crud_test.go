@@ -3,6 +3,10 @@ package quire
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -39,6 +43,9 @@ func TestTable_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return [][]interface{}{{"ID", "Name", "Email", "Age"}}, nil
+				},
 				WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
 					return nil
 				},
@@ -68,6 +75,220 @@ func TestTable_Update(t *testing.T) {
 	}
 }
 
+func TestTable_Update_ReorderedColumns(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Email", "Notes", "Age", "ID", "Name"},
+				{"alice@example.com", "vip", 30.0, 1.0, "Alice"},
+				{"bob@example.com", "", 25.0, 2.0, "Bob"},
+			}, nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+	table := &Table{db: &DB{client: mock}, name: "Users"}
+
+	// Notes isn't mapped, so its cell is sent as nil and left as it is.
+	record := TestUser{ID: 1, Name: "Alicia", Email: "alicia@example.com", Age: 31}
+	want := []interface{}{"alicia@example.com", nil, 31, 1, "Alicia"}
+
+	if err := table.Update(ctx, 0, record); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if got := mock.WriteCalls[0]; got.Range_ != "Users!A2:E2" || !reflect.DeepEqual(got.Values[0], want) {
+		t.Errorf("Update() wrote %v to %s, want %v to Users!A2:E2", got.Values[0], got.Range_, want)
+	}
+
+	if err := table.UpdateWhere(ctx, "Name", "=", "Bob", record); err != nil {
+		t.Fatalf("UpdateWhere() unexpected error = %v", err)
+	}
+	wantBatch := map[string][][]interface{}{"Users!A3:E3": {want}}
+	if got := mock.BatchWriteCalls[0].Data; !reflect.DeepEqual(got, wantBatch) {
+		t.Errorf("UpdateWhere() wrote %v, want %v", got, wantBatch)
+	}
+
+	partial := struct {
+		Age int `quire:"Age"`
+		ID  int `quire:"ID"`
+	}{Age: 40, ID: 2}
+	if err := table.Update(ctx, 1, partial); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if got := mock.WriteCalls[1]; got.Range_ != "Users!C3:D3" || !reflect.DeepEqual(got.Values[0], []interface{}{40, 2}) {
+		t.Errorf("Update() of a partial struct wrote %v to %s, want [40 2] to Users!C3:D3", got.Values[0], got.Range_)
+	}
+}
+
+func TestTable_UpdateFields(t *testing.T) {
+	ctx := context.Background()
+	sheet := [][]interface{}{
+		{"ID", "Name", "Status", "Email"},
+		{1.0, "Alice", "pending", "alice@test.com"},
+		{2.0, "Bob", "active", "bob@test.com"},
+		{3.0, "Carol", "pending", "carol@test.com"},
+	}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return sheet, nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			applyWrite(sheet, range_, values)
+			return nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			for range_, values := range data {
+				applyWrite(sheet, range_, values)
+			}
+			return nil
+		},
+	}
+	table := &Table{db: &DB{client: mock}, name: "Users"}
+
+	if err := table.UpdateFields(ctx, 1, map[string]interface{}{"Status": "closed"}); err != nil {
+		t.Fatalf("UpdateFields() unexpected error = %v", err)
+	}
+	if got := mock.WriteCalls[0]; got.Range_ != "Users!C3:C3" {
+		t.Errorf("UpdateFields() range = %s, want Users!C3:C3", got.Range_)
+	}
+
+	err := table.UpdateFieldsWhere(ctx, "Status", "=", "pending", map[string]interface{}{"Status": "active", "ID": 7})
+	if err != nil {
+		t.Fatalf("UpdateFieldsWhere() unexpected error = %v", err)
+	}
+	if len(mock.BatchWriteCalls) != 1 {
+		t.Fatalf("UpdateFieldsWhere() made %d batch writes, want 1", len(mock.BatchWriteCalls))
+	}
+
+	want := [][]interface{}{
+		{"ID", "Name", "Status", "Email"},
+		{7, "Alice", "active", "alice@test.com"},
+		{2.0, "Bob", "closed", "bob@test.com"},
+		{7, "Carol", "active", "carol@test.com"},
+	}
+	if !reflect.DeepEqual(sheet, want) {
+		t.Errorf("sheet after partial updates = %v, want %v", sheet, want)
+	}
+
+	err = table.UpdateFields(ctx, 0, map[string]interface{}{"Status": "x", "Phone": "555"})
+	if err == nil || !strings.Contains(err.Error(), `column "Phone" not found`) {
+		t.Errorf("UpdateFields() error = %v, want column not found", err)
+	}
+	if len(mock.WriteCalls) != 1 {
+		t.Errorf("UpdateFields() with an unknown column wrote anyway")
+	}
+}
+
+// applyWrite copies a single-row write such as "Users!B3:C3" into sheet,
+// skipping nil cells as the Sheets API does.
+func applyWrite(sheet [][]interface{}, range_ string, values [][]interface{}) {
+	_, cells, _ := strings.Cut(range_, "!")
+	start, _, _ := strings.Cut(cells, ":")
+	col, row, _ := parseCellRef(start)
+	for i, v := range values[0] {
+		if v != nil {
+			sheet[row-1][col+i] = v
+		}
+	}
+}
+
+func TestTable_UpdateWhere_SingleBatch(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Status"},
+				{1.0, "Alice", "pending"},
+				{2.0, "Bob", "pending"},
+				{3.0, "Charlie", "active"},
+				{4.0, "Dave", "pending"},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+
+	table := &Table{db: &DB{client: mock}, name: "Users"}
+	if err := table.UpdateWhere(ctx, "Status", "=", "pending", TestUser{ID: 9, Name: "X"}); err != nil {
+		t.Fatalf("UpdateWhere() unexpected error = %v", err)
+	}
+
+	if len(mock.BatchWriteCalls) != 1 {
+		t.Fatalf("UpdateWhere() made %d batch writes, want 1", len(mock.BatchWriteCalls))
+	}
+	var ranges []string
+	for range_ := range mock.BatchWriteCalls[0].Data {
+		ranges = append(ranges, range_)
+	}
+	sort.Strings(ranges)
+	// TestUser's Email and Age have no column here, so only ID and Name
+	// are written.
+	want := []string{"Users!A2:B2", "Users!A3:B3", "Users!A5:B5"}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("UpdateWhere() wrote ranges %v, want %v", ranges, want)
+	}
+}
+
+func TestTable_UpdateWhere_SplitsBatches(t *testing.T) {
+	ctx := context.Background()
+	data := [][]interface{}{{"ID", "Name", "Status"}}
+	for i := 1; i <= 7; i++ {
+		data = append(data, []interface{}{float64(i), "User", "pending"})
+	}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return data, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+
+	table := (&DB{client: mock, writeBatchSize: 3}).Table("Users")
+	if err := table.UpdateWhere(ctx, "Status", "=", "pending", TestUser{ID: 9, Name: "X"}); err != nil {
+		t.Fatalf("UpdateWhere() unexpected error = %v", err)
+	}
+
+	var sizes []int
+	written := make(map[string]bool)
+	for _, call := range mock.BatchWriteCalls {
+		sizes = append(sizes, len(call.Data))
+		for range_ := range call.Data {
+			written[range_] = true
+		}
+	}
+	if !reflect.DeepEqual(sizes, []int{3, 3, 1}) {
+		t.Errorf("UpdateWhere() batch sizes = %v, want [3 3 1]", sizes)
+	}
+	for row := 2; row <= 8; row++ {
+		if range_ := fmt.Sprintf("Users!A%d:B%d", row, row); !written[range_] {
+			t.Errorf("UpdateWhere() did not write %s", range_)
+		}
+	}
+
+	mock.Reset()
+	writeErr := errors.New("request too large")
+	mock.BatchWriteFunc = func(ctx context.Context, data map[string][][]interface{}) error {
+		if len(mock.BatchWriteCalls) == 2 {
+			return writeErr
+		}
+		return nil
+	}
+	err := table.UpdateFieldsWhere(ctx, "Status", "=", "pending", map[string]interface{}{"Status": "done"})
+	if !errors.Is(err, writeErr) || !strings.Contains(err.Error(), "failed to update 4 rows after updating 3") {
+		t.Errorf("UpdateFieldsWhere() error = %v, want the count of rows updated before the failure", err)
+	}
+	if len(mock.BatchWriteCalls) != 2 {
+		t.Errorf("UpdateFieldsWhere() made %d batch writes, want it to stop at the failed one", len(mock.BatchWriteCalls))
+	}
+}
+
 func TestTable_UpdateWhere(t *testing.T) {
 	ctx := context.Background()
 
@@ -127,8 +348,8 @@ func TestTable_UpdateWhere(t *testing.T) {
 					}
 					return tt.mockData, nil
 				},
-				WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
-					writeCount++
+				BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+					writeCount += len(data)
 					return nil
 				},
 			}
@@ -151,12 +372,553 @@ func TestTable_UpdateWhere(t *testing.T) {
 			}
 
 			if writeCount != tt.expectedRows {
-				t.Errorf("UpdateWhere() expected %d write calls, got %d", tt.expectedRows, writeCount)
+				t.Errorf("UpdateWhere() expected %d rows written, got %d", tt.expectedRows, writeCount)
+			}
+			if len(mock.WriteCalls) != 0 {
+				t.Errorf("UpdateWhere() made %d single-range writes, want none", len(mock.WriteCalls))
+			}
+		})
+	}
+}
+
+var userHeaders = []interface{}{"ID", "Name", "Email", "Age"}
+
+// headerAndRow returns a ReadFunc serving headers for the header row and
+// row for any other range.
+func headerAndRow(headers, row []interface{}) func(ctx context.Context, range_ string) ([][]interface{}, error) {
+	return func(ctx context.Context, range_ string) ([][]interface{}, error) {
+		if strings.HasSuffix(range_, "!1:1") {
+			return [][]interface{}{headers}, nil
+		}
+		return [][]interface{}{row}, nil
+	}
+}
+
+func TestTable_UpdateIfUnchanged(t *testing.T) {
+	ctx := context.Background()
+	expected := TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30}
+	record := TestUser{ID: 1, Name: "Alice Updated", Email: "alice@test.com", Age: 31}
+
+	tests := []struct {
+		name        string
+		current     []interface{}
+		wantWritten bool
+	}{
+		{
+			name:        "row unchanged",
+			current:     []interface{}{1.0, "Alice", "alice@test.com", 30.0},
+			wantWritten: true,
+		},
+		{
+			name:        "row changed",
+			current:     []interface{}{1.0, "Alice", "alice@other.com", 30.0},
+			wantWritten: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: headerAndRow(userHeaders, tt.current),
+				WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return nil
+				},
+			}
+
+			db := &DB{client: mock}
+			table := &Table{db: db, name: "Users"}
+
+			written, err := table.UpdateIfUnchanged(ctx, 0, expected, record)
+			if err != nil {
+				t.Fatalf("UpdateIfUnchanged() unexpected error = %v", err)
+			}
+
+			if written != tt.wantWritten {
+				t.Errorf("UpdateIfUnchanged() = %v, want %v", written, tt.wantWritten)
+			}
+
+			if mock.ReadCalls[1].Range_ != "Users!A2:D2" {
+				t.Errorf("UpdateIfUnchanged() read range = %v, want Users!A2:D2", mock.ReadCalls[1].Range_)
+			}
+
+			wantWrites := 0
+			if tt.wantWritten {
+				wantWrites = 1
+			}
+			if len(mock.WriteCalls) != wantWrites {
+				t.Errorf("UpdateIfUnchanged() expected %d write calls, got %d", wantWrites, len(mock.WriteCalls))
 			}
 		})
 	}
 }
 
+type Balance struct {
+	ID      int     `quire:"ID"`
+	Active  bool    `quire:"Active"`
+	Balance float64 `quire:"Balance"`
+}
+
+func TestTable_UpdateIfUnchanged_FormattedCells(t *testing.T) {
+	expected := Balance{ID: 1, Active: true, Balance: 2500000}
+	record := Balance{ID: 1, Active: false, Balance: 2500000}
+
+	tests := []struct {
+		name        string
+		current     []interface{}
+		wantWritten bool
+	}{
+		{"formatted", []interface{}{"1", "TRUE", "2500000"}, true},
+		{"unformatted", []interface{}{1.0, true, 2500000.0}, true},
+		{"bool changed", []interface{}{"1", "FALSE", "2500000"}, false},
+		{"float changed", []interface{}{"1", "TRUE", "2500001"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: headerAndRow([]interface{}{"ID", "Active", "Balance"}, tt.current),
+				WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return nil
+				},
+			}
+			table := (&DB{client: mock}).Table("Accounts")
+
+			written, err := table.UpdateIfUnchanged(context.Background(), 0, expected, record)
+			if err != nil {
+				t.Fatalf("UpdateIfUnchanged() unexpected error = %v", err)
+			}
+			if written != tt.wantWritten {
+				t.Errorf("UpdateIfUnchanged() = %v, want %v", written, tt.wantWritten)
+			}
+		})
+	}
+}
+
+func TestSameCell(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{"TRUE", true, true},
+		{"false", false, true},
+		{"TRUE", false, false},
+		{"1000000", 1000000.0, true},
+		{1e6, 1000000.0, true},
+		{"1e+06", 1000000.0, false},
+		{"Alice", "alice", false},
+		{nil, "", true},
+	}
+	for _, tt := range tests {
+		if got := sameCell(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameCell(%#v, %#v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTable_UpdateIfUnchanged_NegativeIndex(t *testing.T) {
+	db := &DB{client: &MockSheetsClient{}}
+	table := &Table{db: db, name: "Users"}
+
+	_, err := table.UpdateIfUnchanged(context.Background(), -1, TestUser{}, TestUser{})
+	if err == nil {
+		t.Error("UpdateIfUnchanged() expected error for negative index")
+	}
+}
+
+func TestTable_UpdateDiff(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		current     []interface{}
+		record      TestUser
+		wantChanged int
+		wantRange   string
+		wantValues  []interface{}
+	}{
+		{
+			name:        "single cell differs",
+			current:     []interface{}{1.0, "Alice", "alice@test.com", 30.0},
+			record:      TestUser{ID: 1, Name: "Alice", Email: "alice@new.com", Age: 30},
+			wantChanged: 1,
+			wantRange:   "Users!C2:C2",
+			wantValues:  []interface{}{"alice@new.com"},
+		},
+		{
+			name:        "non-adjacent cells differ",
+			current:     []interface{}{1.0, "Alice", "alice@test.com", 30.0},
+			record:      TestUser{ID: 1, Name: "Alicia", Email: "alice@test.com", Age: 31},
+			wantChanged: 2,
+			wantRange:   "Users!B2:D2",
+			wantValues:  []interface{}{"Alicia", nil, 31},
+		},
+		{
+			name:        "missing trailing cells",
+			current:     []interface{}{1.0, "Alice"},
+			record:      TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+			wantChanged: 2,
+			wantRange:   "Users!C2:D2",
+			wantValues:  []interface{}{"alice@test.com", 30},
+		},
+		{
+			name:        "nothing changed",
+			current:     []interface{}{1.0, "Alice", "alice@test.com", 30.0},
+			record:      TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+			wantChanged: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: headerAndRow(userHeaders, tt.current),
+				WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return nil
+				},
+			}
+
+			db := &DB{client: mock}
+			table := &Table{db: db, name: "Users"}
+
+			changed, err := table.UpdateDiff(ctx, 0, tt.record)
+			if err != nil {
+				t.Fatalf("UpdateDiff() unexpected error = %v", err)
+			}
+
+			if changed != tt.wantChanged {
+				t.Errorf("UpdateDiff() = %d, want %d", changed, tt.wantChanged)
+			}
+
+			if tt.wantChanged == 0 {
+				if len(mock.WriteCalls) != 0 {
+					t.Errorf("UpdateDiff() expected no writes, got %d", len(mock.WriteCalls))
+				}
+				return
+			}
+
+			if len(mock.WriteCalls) != 1 {
+				t.Fatalf("UpdateDiff() expected 1 write call, got %d", len(mock.WriteCalls))
+			}
+			if mock.WriteCalls[0].Range_ != tt.wantRange {
+				t.Errorf("UpdateDiff() range = %v, want %v", mock.WriteCalls[0].Range_, tt.wantRange)
+			}
+			if !reflect.DeepEqual(mock.WriteCalls[0].Values[0], tt.wantValues) {
+				t.Errorf("UpdateDiff() values = %v, want %v", mock.WriteCalls[0].Values[0], tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestTable_UpdateDiff_ReorderedColumns(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: headerAndRow(
+			[]interface{}{"Email", "Age", "ID", "Name"},
+			[]interface{}{"alice@test.com", 30.0, 1.0, "Alice"},
+		),
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	changed, err := table.UpdateDiff(context.Background(), 0, TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 31})
+	if err != nil {
+		t.Fatalf("UpdateDiff() unexpected error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("UpdateDiff() = %d, want 1 (only Age)", changed)
+	}
+	if len(mock.WriteCalls) != 1 || mock.WriteCalls[0].Range_ != "Users!B2:B2" ||
+		!reflect.DeepEqual(mock.WriteCalls[0].Values, [][]interface{}{{31}}) {
+		t.Errorf("UpdateDiff() writes = %+v, want 31 written to Users!B2:B2", mock.WriteCalls)
+	}
+}
+
+func TestTable_UpdateDiff_FormattedCells(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: headerAndRow(
+			[]interface{}{"ID", "Active", "Balance"},
+			[]interface{}{"1", "TRUE", "2500000"},
+		),
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Accounts")
+
+	changed, err := table.UpdateDiff(context.Background(), 0, Balance{ID: 1, Active: true, Balance: 2500000})
+	if err != nil {
+		t.Fatalf("UpdateDiff() unexpected error = %v", err)
+	}
+	if changed != 0 || len(mock.WriteCalls) != 0 {
+		t.Errorf("UpdateDiff() = %d with %d writes, want no changes", changed, len(mock.WriteCalls))
+	}
+}
+
+func TestTable_UpdateIfUnchanged_ReorderedColumns(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: headerAndRow(
+			[]interface{}{"Name", "ID", "Age", "Email"},
+			[]interface{}{"Alice", 1.0, 30.0, "alice@test.com"},
+		),
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	expected := TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30}
+	record := TestUser{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 31}
+	written, err := table.UpdateIfUnchanged(context.Background(), 0, expected, record)
+	if err != nil {
+		t.Fatalf("UpdateIfUnchanged() unexpected error = %v", err)
+	}
+	if !written {
+		t.Fatal("UpdateIfUnchanged() = false, want true for a matching row in a reordered sheet")
+	}
+	want := [][]interface{}{{"Alice", 1, 31, "alice@test.com"}}
+	if mock.WriteCalls[0].Range_ != "Users!A2:D2" || !reflect.DeepEqual(mock.WriteCalls[0].Values, want) {
+		t.Errorf("UpdateIfUnchanged() wrote %+v, want %v to Users!A2:D2", mock.WriteCalls[0], want)
+	}
+}
+
+func TestTable_UpdateDiff_SkipsHoles(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: headerAndRow(
+			[]interface{}{"ID", "Name", "Total", "Email", "Notes", "Comment"},
+			[]interface{}{1.0, "Alice", "120", "alice@test.com"},
+		),
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	// Name is omitted and Total is readonly, so neither counts as a change.
+	changed, err := table.UpdateDiff(context.Background(), 0, TaggedRecord{ID: 1, Email: "alice@test.com", Comment: "vip"})
+	if err != nil {
+		t.Fatalf("UpdateDiff() unexpected error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("UpdateDiff() = %d, want 1 (only Comment)", changed)
+	}
+	if len(mock.WriteCalls) != 1 || mock.WriteCalls[0].Range_ != "Users!F2:F2" {
+		t.Errorf("UpdateDiff() writes = %+v, want one write to Users!F2:F2", mock.WriteCalls)
+	}
+}
+
+func TestTable_Upsert(t *testing.T) {
+	ctx := context.Background()
+	sheet := [][]interface{}{
+		{"ID", "Name", "Email", "Age"},
+		{1.0, "Alice", "alice@test.com", 30.0},
+		{2.0, "Bob", "bob@test.com", 25.0},
+		{2.0, "Bobby", "bobby@test.com", 26.0},
+	}
+
+	tests := []struct {
+		name        string
+		record      TestUser
+		wantWrites  []string
+		wantAppends int
+	}{
+		{
+			name:        "insert new key",
+			record:      TestUser{ID: 3, Name: "Carol"},
+			wantAppends: 1,
+		},
+		{
+			name:       "update existing key",
+			record:     TestUser{ID: 1, Name: "Alice Smith"},
+			wantWrites: []string{"Users!A2:D2"},
+		},
+		{
+			name:       "update every duplicate key",
+			record:     TestUser{ID: 2, Name: "Robert"},
+			wantWrites: []string{"Users!A3:D3", "Users!A4:D4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return sheet, nil
+				},
+				BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+					return nil
+				},
+				AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return nil
+				},
+			}
+
+			db := &DB{client: mock}
+			table := &Table{db: db, name: "Users"}
+
+			if err := table.Upsert(ctx, "ID", tt.record); err != nil {
+				t.Fatalf("Upsert() unexpected error = %v", err)
+			}
+
+			var writes []string
+			for _, call := range mock.BatchWriteCalls {
+				for range_ := range call.Data {
+					writes = append(writes, range_)
+				}
+			}
+			sort.Strings(writes)
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("Upsert() writes = %v, want %v", writes, tt.wantWrites)
+			}
+			if len(mock.AppendCalls) != tt.wantAppends {
+				t.Errorf("Upsert() appends = %d, want %d", len(mock.AppendCalls), tt.wantAppends)
+			}
+		})
+	}
+}
+
+func TestTable_Upsert_PartialStruct(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Notes", "Name", "Email", "Age"},
+				{1.0, "keep me", "Alice", "alice@test.com", 30.0},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	type nameOnly struct {
+		ID   int    `quire:"ID"`
+		Name string `quire:"Name"`
+	}
+
+	if err := table.Upsert(ctx, "ID", nameOnly{ID: 1, Name: "Alice Smith"}); err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+
+	// Notes sits between ID and Name and is sent as nil, which the API
+	// skips; Email and Age are outside the written span.
+	wantBatch := map[string][][]interface{}{"Users!A2:C2": {{1, nil, "Alice Smith"}}}
+	if got := mock.BatchWriteCalls[0].Data; !reflect.DeepEqual(got, wantBatch) {
+		t.Errorf("Upsert() wrote %v, want %v", got, wantBatch)
+	}
+
+	if err := table.Upsert(ctx, "ID", nameOnly{ID: 2, Name: "Bob"}); err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+	if got, want := mock.AppendCalls[0].Values[0], []interface{}{2, nil, "Bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Upsert() appended %v, want %v", got, want)
+	}
+}
+
+func TestTable_Upsert_EmptySheet(t *testing.T) {
+	ctx := context.Background()
+	var sheet [][]interface{}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return sheet, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			sheet = append(sheet, values...)
+			return nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	if err := table.Upsert(ctx, "ID", TestUser{ID: 1, Name: "Alice"}); err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+	want := [][]interface{}{
+		{"ID", "Name", "Email", "Age"},
+		{1, "Alice", "", 0},
+	}
+	if len(mock.AppendCalls) != 1 || !reflect.DeepEqual(mock.AppendCalls[0].Values, want) {
+		t.Fatalf("Upsert() appends = %+v, want one append of %v", mock.AppendCalls, want)
+	}
+
+	// The header row written above lets the next Upsert find the key.
+	if err := table.Upsert(ctx, "ID", TestUser{ID: 1, Name: "Alice Smith"}); err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+	if len(mock.AppendCalls) != 1 {
+		t.Errorf("Upsert() appends = %d, want 1", len(mock.AppendCalls))
+	}
+	wantBatch := map[string][][]interface{}{"Users!A2:D2": {{1, "Alice Smith", "", 0}}}
+	if len(mock.BatchWriteCalls) != 1 || !reflect.DeepEqual(mock.BatchWriteCalls[0].Data, wantBatch) {
+		t.Errorf("Upsert() batch writes = %+v, want %v", mock.BatchWriteCalls, wantBatch)
+	}
+}
+
+func TestTable_Upsert_FloatKey(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Code", "Name"},
+				{"1000000", "Alice"},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	type coded struct {
+		Code float64 `quire:"Code"`
+		Name string  `quire:"Name"`
+	}
+
+	table := (&DB{client: mock}).Table("Users")
+	if err := table.Upsert(ctx, "Code", coded{Code: 1e6, Name: "Alice Smith"}); err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+	if len(mock.AppendCalls) != 0 {
+		t.Errorf("Upsert() appended %v, want the existing row updated", mock.AppendCalls[0].Values)
+	}
+	want := map[string][][]interface{}{"Users!A2:B2": {{1e6, "Alice Smith"}}}
+	if len(mock.BatchWriteCalls) != 1 || !reflect.DeepEqual(mock.BatchWriteCalls[0].Data, want) {
+		t.Errorf("Upsert() batch writes = %+v, want %v", mock.BatchWriteCalls, want)
+	}
+}
+
+func TestTable_Upsert_UnknownKey(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"ID", "Name"}}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	if err := table.Upsert(context.Background(), "Missing", TestUser{ID: 1}); err == nil {
+		t.Error("Upsert() expected error for a key with no matching field")
+	}
+	if err := table.Upsert(context.Background(), "Email", TestUser{ID: 1}); err == nil {
+		t.Error("Upsert() expected error for a key column missing from the sheet")
+	}
+}
+
 func TestTable_Delete(t *testing.T) {
 	ctx := context.Background()
 
@@ -311,6 +1073,230 @@ func TestTable_DeleteWhere(t *testing.T) {
 	}
 }
 
+func TestTable_CountWhere(t *testing.T) {
+	ctx := context.Background()
+	data := [][]interface{}{
+		{"ID", "Name", "Status", "Age"},
+		{1.0, "Alice", "deleted", 30.0},
+		{2.0, "Bob", "active", 25.0},
+		{3.0, "Charlie", "deleted", 41.0},
+		{4.0, "Dana", nil, 19.0},
+	}
+
+	tests := []struct {
+		column, operator string
+		value            interface{}
+		want             int
+	}{
+		{"Status", "=", "deleted", 2},
+		{"Status", "!=", "deleted", 1}, // blank cells match no condition
+		{"Age", ">", 20, 3},
+		{"Name", "in", []string{"Bob", "Dana", "Eve"}, 2},
+		{"Status", "=", "archived", 0},
+		{"Missing", "=", "x", 0},
+	}
+
+	for _, tt := range tests {
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return data, nil
+			},
+			DeleteRowsFunc: func(ctx context.Context, sheetName string, rowIndices []int) error {
+				return nil
+			},
+		}
+		table := &Table{db: &DB{client: mock}, name: "Users"}
+
+		got, err := table.CountWhere(ctx, tt.column, tt.operator, tt.value)
+		if err != nil {
+			t.Fatalf("CountWhere(%s %s %v) unexpected error = %v", tt.column, tt.operator, tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("CountWhere(%s %s %v) = %d, want %d", tt.column, tt.operator, tt.value, got, tt.want)
+		}
+
+		if err := table.DeleteWhere(ctx, tt.column, tt.operator, tt.value); err != nil {
+			t.Fatalf("DeleteWhere() unexpected error = %v", err)
+		}
+		deleted := 0
+		for _, call := range mock.DeleteRowsCalls {
+			deleted += len(call.RowIndices)
+		}
+		if deleted != got {
+			t.Errorf("DeleteWhere(%s %s %v) deleted %d rows, CountWhere said %d", tt.column, tt.operator, tt.value, deleted, got)
+		}
+	}
+
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return nil, errors.New("read error")
+		},
+	}
+	if _, err := (&Table{db: &DB{client: mock}, name: "Users"}).CountWhere(ctx, "Status", "=", "x"); err == nil {
+		t.Error("CountWhere() expected error but got nil")
+	}
+}
+
+func TestTable_Exists(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		data     [][]interface{}
+		readErr  error
+		column   string
+		operator string
+		value    interface{}
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name: "found",
+			data: [][]interface{}{
+				{"ID", "Email"},
+				{1.0, "alice@test.com"},
+				{2.0, "bob@test.com"},
+			},
+			column: "Email", operator: "=", value: "bob@test.com",
+			want: true,
+		},
+		{
+			name: "not found",
+			data: [][]interface{}{
+				{"ID", "Email"},
+				{1.0, "alice@test.com"},
+			},
+			column: "Email", operator: "=", value: "carol@test.com",
+		},
+		{
+			name:   "header only",
+			data:   [][]interface{}{{"ID", "Email"}},
+			column: "ID", operator: ">", value: 0,
+		},
+		{
+			name:   "empty sheet",
+			data:   [][]interface{}{},
+			column: "ID", operator: ">", value: 0,
+		},
+		{
+			name:    "read error",
+			readErr: errors.New("read error"),
+			column:  "ID", operator: "=", value: 1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return tt.data, tt.readErr
+				},
+			}
+			table := &Table{db: &DB{client: mock}, name: "Users"}
+
+			got, err := table.Exists(ctx, tt.column, tt.operator, tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, tt.readErr) {
+					t.Errorf("Exists() error = %v, want %v", err, tt.readErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exists() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Exists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_TruncateAndClear(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		opts          TableOptions
+		header        []interface{}
+		wantTruncate  string
+		wantClear     string
+		wantClearRead bool
+	}{
+		{
+			name:         "table at A1",
+			header:       []interface{}{"ID", "Name", "Email", "Age"},
+			wantTruncate: "Users!A2:D",
+			wantClear:    "Users",
+		},
+		{
+			name:          "anchored table",
+			opts:          TableOptions{Anchor: "C5"},
+			header:        []interface{}{"", "", "ID", "Name"},
+			wantTruncate:  "Users!C6:D",
+			wantClear:     "Users!C5:D",
+			wantClearRead: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return [][]interface{}{tt.header}, nil
+				},
+				ClearFunc: func(ctx context.Context, range_ string) error {
+					return nil
+				},
+			}
+
+			db := &DB{client: mock}
+			table := db.TableWithOptions("Users", tt.opts)
+
+			if err := table.Truncate(ctx); err != nil {
+				t.Fatalf("Truncate() unexpected error = %v", err)
+			}
+			if err := table.Clear(ctx); err != nil {
+				t.Fatalf("Clear() unexpected error = %v", err)
+			}
+
+			if len(mock.ClearCalls) != 2 {
+				t.Fatalf("expected 2 clear calls, got %d", len(mock.ClearCalls))
+			}
+			if got := mock.ClearCalls[0].Range_; got != tt.wantTruncate {
+				t.Errorf("Truncate() range = %q, want %q", got, tt.wantTruncate)
+			}
+			if got := mock.ClearCalls[1].Range_; got != tt.wantClear {
+				t.Errorf("Clear() range = %q, want %q", got, tt.wantClear)
+			}
+
+			wantReads := 1
+			if tt.wantClearRead {
+				wantReads = 2
+			}
+			if len(mock.ReadCalls) != wantReads {
+				t.Errorf("expected %d reads, got %d", wantReads, len(mock.ReadCalls))
+			}
+		})
+	}
+}
+
+func TestTable_Truncate_EmptySheet(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return nil, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	if err := db.Table("Users").Truncate(context.Background()); err != nil {
+		t.Fatalf("Truncate() unexpected error = %v", err)
+	}
+	if len(mock.ClearCalls) != 0 {
+		t.Errorf("Truncate() on an empty sheet should not clear, got %d calls", len(mock.ClearCalls))
+	}
+}
+
 func TestColumnIndexToLetter(t *testing.T) {
 	tests := []struct {
 		index    int
@@ -378,3 +1364,167 @@ func TestMatchesFilter(t *testing.T) {
 		})
 	}
 }
+
+type OrderLine struct {
+	Item  string  `quire:"Item"`
+	Price float64 `quire:"Price"`
+	Qty   int     `quire:"Qty"`
+	Total float64 `quire:"Total,readonly"`
+}
+
+func TestTable_ReadonlyColumns(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Item", "Price", "Qty", "Total"},
+				{"pen", 1.5, 4.0, 6.0},
+			}, nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Orders"}
+
+	var lines []OrderLine
+	if err := table.Query().Get(ctx, &lines); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(lines) != 1 || lines[0].Total != 6 {
+		t.Fatalf("Get() = %+v, want Total scanned from the sheet", lines)
+	}
+
+	stale := OrderLine{Item: "pen", Price: 2, Qty: 4, Total: 6}
+	if err := table.Insert(ctx, []OrderLine{stale}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	if err := table.Update(ctx, 0, stale); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+
+	want := []interface{}{"pen", 2.0, 4, nil}
+	if got := mock.AppendCalls[0].Values[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Insert() values = %v, want %v", got, want)
+	}
+	// Update doesn't send the readonly Total cell at all.
+	if got := mock.WriteCalls[0]; got.Range_ != "Orders!A2:C2" || !reflect.DeepEqual(got.Values[0], want[:3]) {
+		t.Errorf("Update() wrote %v to %s, want %v to Orders!A2:C2", got.Values[0], got.Range_, want[:3])
+	}
+}
+
+func TestTable_ReadAfterID(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice"},
+				{2.0, "Bob"},
+				{"10", "Carol"},
+				{9.0, "Dave"},
+				{"", "Blank"},
+				{"n/a", "Text"},
+				{11.0, "Eve"},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Events")
+
+	tests := []struct {
+		afterID interface{}
+		want    []int
+	}{
+		{0, []int{1, 2, 9, 10, 11}},
+		{2, []int{9, 10, 11}},
+		{"9", []int{10, 11}},
+		{9.5, []int{10, 11}},
+		{11, nil},
+	}
+	for _, tt := range tests {
+		var users []TestUser
+		if err := table.ReadAfterID(ctx, "ID", tt.afterID, &users); err != nil {
+			t.Fatalf("ReadAfterID(%v) unexpected error = %v", tt.afterID, err)
+		}
+		var ids []int
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("ReadAfterID(%v) = %v, want %v", tt.afterID, ids, tt.want)
+		}
+	}
+
+	var users []TestUser
+	if err := table.ReadAfterID(ctx, "Seq", 0, &users); err == nil {
+		t.Error("ReadAfterID() expected error for an unknown ID column")
+	}
+}
+
+func TestTable_WriteRows(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"ID", "Name", "Email", "Age"}}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	err := table.WriteRows(ctx, map[int]interface{}{
+		2:  TestUser{ID: 1, Name: "Alice", Email: "alice@example.com", Age: 30},
+		10: &TestUser{ID: 9, Name: "Ivan", Email: "ivan@example.com", Age: 41},
+	})
+	if err != nil {
+		t.Fatalf("WriteRows() unexpected error = %v", err)
+	}
+
+	if len(mock.BatchWriteCalls) != 1 || len(mock.WriteCalls) != 0 {
+		t.Fatalf("WriteRows() made %d batch writes and %d writes, want a single batch write",
+			len(mock.BatchWriteCalls), len(mock.WriteCalls))
+	}
+	want := map[string][][]interface{}{
+		"Users!A2:D2":   {{1, "Alice", "alice@example.com", 30}},
+		"Users!A10:D10": {{9, "Ivan", "ivan@example.com", 41}},
+	}
+	if got := mock.BatchWriteCalls[0].Data; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteRows() wrote %v, want %v", got, want)
+	}
+}
+
+func TestTable_WriteRows_Anchored(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"", "ID", "Name"}}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).TableWithOptions("Users", TableOptions{Anchor: "B3"})
+
+	if err := table.WriteRows(context.Background(), map[int]interface{}{4: TestUser{ID: 1, Name: "Alice"}}); err != nil {
+		t.Fatalf("WriteRows() unexpected error = %v", err)
+	}
+	want := map[string][][]interface{}{"Users!B4:C4": {{1, "Alice"}}}
+	if got := mock.BatchWriteCalls[0].Data; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteRows() wrote %v, want %v", got, want)
+	}
+
+	mock.Reset()
+	for _, row := range []int{3, 1, 0} {
+		if err := table.WriteRows(context.Background(), map[int]interface{}{row: TestUser{ID: 1}}); err == nil {
+			t.Errorf("WriteRows() expected error for row %d at or above the header", row)
+		}
+	}
+	if len(mock.BatchWriteCalls) != 0 {
+		t.Errorf("WriteRows() wrote %v despite an invalid row", mock.BatchWriteCalls)
+	}
+}
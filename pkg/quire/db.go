@@ -4,48 +4,232 @@ package quire
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
 )
 
+// defaultBatchSize is the number of rows Insert appends per API call when
+// Config.BatchSize is not set.
+const defaultBatchSize = 1000
+
+// defaultWriteBatchSize is the number of rows a multi-row update writes per
+// API call when Config.WriteBatchSize is not set.
+const defaultWriteBatchSize = 500
+
 // DB represents a database connection to a Google Sheet.
 type DB struct {
-	spreadsheetID string
-	client        SheetsClient
+	spreadsheetID    string
+	client           SheetsClient
+	batchSize        int
+	writeBatchSize   int
+	autoExpand       bool
+	filterTrace      func(rowIndex int, filter Filter, matched bool)
+	normalizeHeaders bool
+	lenientHeaders   bool
+	defaultTagFunc   func(fieldName string) string
+
+	// locks holds a *sync.Mutex per sheet name, serializing this DB's
+	// read-modify-write operations such as UpsertIncrement.
+	locks sync.Map
+
+	// namedFields caches each struct type's fields as named by
+	// defaultTagFunc.
+	namedFields sync.Map
 }
 
 // SheetsClient defines the interface for Google Sheets operations.
 type SheetsClient interface {
 	Read(ctx context.Context, range_ string) ([][]interface{}, error)
+	BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error)
 	Write(ctx context.Context, range_ string, values [][]interface{}) error
+	BatchWrite(ctx context.Context, data map[string][][]interface{}) error
 	Append(ctx context.Context, range_ string, values [][]interface{}) error
 	Clear(ctx context.Context, range_ string) error
 	DeleteRows(ctx context.Context, sheetName string, rowIndices []int) error
+	ListSheets(ctx context.Context) ([]SheetProperties, error)
+	AddSheet(ctx context.Context, sheetName string) error
+	DeleteSheet(ctx context.Context, sheetName string) error
+	ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error
+}
+
+// SheetProperties describes a sheet (tab) in the spreadsheet.
+type SheetProperties struct {
+	SheetID int64
+	Title   string
+
+	// RowCount and ColumnCount are the size of the sheet's grid, which
+	// includes empty cells, not the extent of the data in it.
+	RowCount    int
+	ColumnCount int
 }
 
 // Config holds database configuration.
 type Config struct {
 	SpreadsheetID string
 	Credentials   []byte // Service account JSON
+
+	// TokenSource authenticates with an existing OAuth2 token source, such
+	// as one from a user login flow, instead of Credentials. At most one of
+	// the two may be set.
+	TokenSource oauth2.TokenSource
+
+	// UseDefaultCredentials authenticates with Google's Application Default
+	// Credentials (GOOGLE_APPLICATION_CREDENTIALS, gcloud, or the GCP
+	// metadata server) when neither TokenSource nor Credentials is set.
+	// Precedence is TokenSource, then Credentials, then default credentials.
+	UseDefaultCredentials bool
+
+	// ValueInputOption controls how written values are interpreted: "RAW"
+	// (the default) stores them as given, "USER_ENTERED" parses them as if
+	// typed into the sheet, so "=A1*2" becomes a formula and "2024-01-31"
+	// a date.
+	ValueInputOption string
+
+	// ValueRenderOption controls how read values are rendered:
+	// "FORMATTED_VALUE" (the default) as displayed, "UNFORMATTED_VALUE"
+	// without number formatting, or "FORMULA" with formulas instead of
+	// their results, which Hyperlink fields need to read the URL. Dates are
+	// read as displayed in every mode.
+	ValueRenderOption string
+
+	// Scopes overrides the OAuth scopes requested for the credentials.
+	// Use sheets.SpreadsheetsReadonlyScope for read-only access.
+	Scopes []string
+
+	// BatchSize is the maximum number of rows sent in a single append.
+	// Larger inserts are split into sequential batches. Defaults to 1000.
+	BatchSize int
+
+	// WriteBatchSize is the maximum number of rows written in a single
+	// batch update by UpdateWhere, UpdateFieldsWhere and UpdateByKey, so a
+	// change to many rows doesn't exceed the API's request size limit.
+	// Larger updates are split into sequential requests. Defaults to 500.
+	WriteBatchSize int
+
+	// RetryAttempts is the total number of attempts made for a call that
+	// fails with a rate-limit (429) or server (5xx) error. Values below 2
+	// disable retries. Calls that aren't idempotent (inserts, row deletes
+	// and sheet growth) are retried only on 429, so a retry can never apply
+	// them twice.
+	RetryAttempts int
+
+	// RetryBaseDelay is the delay before the first retry; it doubles on
+	// each subsequent attempt, with jitter. Defaults to 500ms.
+	RetryBaseDelay time.Duration
+
+	// AutoExpand makes Insert grow the sheet's grid first when the new rows
+	// or columns would not fit. It costs two extra reads per insert.
+	AutoExpand bool
+
+	// OperationTimeout bounds each call to the Sheets API, including its
+	// retries. Zero means calls are bounded only by the caller's context.
+	OperationTimeout time.Duration
+
+	// CacheTTL enables an in-memory cache of read results, kept for this
+	// long. Any write through this DB drops the cached ranges of the sheet
+	// it touches; changes made by others show up once entries expire.
+	// Zero disables caching.
+	CacheTTL time.Duration
+
+	// NormalizeHeaders trims whitespace around header names and renames
+	// repeated ones with a numeric suffix ("Name", "Name_2") when reading,
+	// so messy sheets can be mapped. Tags must then use the normalized
+	// names.
+	NormalizeHeaders bool
+
+	// LenientHeaders matches struct fields, on reads and header-mapped
+	// writes, and Where, OrderBy, WhereExistsIn, Upsert, UpdateWhere,
+	// DeleteWhere and Columns columns to headers ignoring surrounding
+	// whitespace and case when there is no exact match, so " Name " and
+	// "NAME" both match a Name field.
+	LenientHeaders bool
+
+	// DefaultTagFunc names the column of a struct field whose quire tag
+	// doesn't, for both reads and writes; e.g. a snake_case function maps
+	// a CreatedAt field to "created_at". Nil uses the Go field name.
+	DefaultTagFunc func(fieldName string) string
+
+	// AllowedTables, if non-empty, lists the only sheets this DB may change:
+	// inserts, updates, deletes, clears and sheet creation or growth on any
+	// other sheet fail with ErrTableNotAllowed before reaching the API.
+	// Reads are not restricted. Names are matched exactly.
+	AllowedTables []string
+
+	// FilterTrace, if set, is called for every filter evaluated against a
+	// row while a query filters its results: rowIndex is the row's 0-based
+	// index excluding the header. Use it to see which filter rejected a
+	// row. Nil (the default) disables tracing.
+	FilterTrace func(rowIndex int, filter Filter, matched bool)
 }
 
 // New creates a new DB instance with the provided configuration.
 func New(cfg Config) (*DB, error) {
 	if cfg.SpreadsheetID == "" {
-		return nil, fmt.Errorf("spreadsheet ID is required")
+		return nil, &ConfigError{Field: "SpreadsheetID", Err: ErrSpreadsheetIDRequired}
+	}
+
+	if len(cfg.Credentials) == 0 && cfg.TokenSource == nil && !cfg.UseDefaultCredentials {
+		return nil, &ConfigError{Field: "Credentials", Err: ErrCredentialsRequired}
+	}
+
+	if len(cfg.Credentials) > 0 && cfg.TokenSource != nil {
+		return nil, &ConfigError{Field: "TokenSource", Err: errors.New("only one of credentials and token source may be set")}
+	}
+
+	switch cfg.ValueInputOption {
+	case "", "RAW", "USER_ENTERED":
+	default:
+		return nil, &ConfigError{
+			Field: "ValueInputOption",
+			Err:   fmt.Errorf("invalid value input option %q: must be RAW or USER_ENTERED", cfg.ValueInputOption),
+		}
 	}
 
-	if len(cfg.Credentials) == 0 {
-		return nil, fmt.Errorf("credentials are required")
+	switch cfg.ValueRenderOption {
+	case "", "FORMATTED_VALUE", "UNFORMATTED_VALUE", "FORMULA":
+	default:
+		return nil, &ConfigError{
+			Field: "ValueRenderOption",
+			Err:   fmt.Errorf("invalid value render option %q: must be FORMATTED_VALUE, UNFORMATTED_VALUE or FORMULA", cfg.ValueRenderOption),
+		}
 	}
 
-	client, err := newSheetsClient(cfg.Credentials, cfg.SpreadsheetID)
+	base, err := newSheetsClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sheets client: %w", err)
 	}
 
+	var client SheetsClient = base
+	if cfg.RetryAttempts > 1 {
+		client = newRetryClient(client, retryPolicy{
+			attempts:  cfg.RetryAttempts,
+			baseDelay: cfg.RetryBaseDelay,
+		})
+	}
+	if cfg.OperationTimeout > 0 {
+		client = newTimeoutClient(client, cfg.OperationTimeout)
+	}
+	if cfg.CacheTTL > 0 {
+		client = newCacheClient(client, cfg.CacheTTL)
+	}
+	if len(cfg.AllowedTables) > 0 {
+		client = newAllowClient(client, cfg.AllowedTables)
+	}
+
 	return &DB{
-		spreadsheetID: cfg.SpreadsheetID,
-		client:        client,
+		spreadsheetID:    cfg.SpreadsheetID,
+		client:           client,
+		batchSize:        cfg.BatchSize,
+		writeBatchSize:   cfg.WriteBatchSize,
+		autoExpand:       cfg.AutoExpand,
+		filterTrace:      cfg.FilterTrace,
+		normalizeHeaders: cfg.NormalizeHeaders,
+		lenientHeaders:   cfg.LenientHeaders,
+		defaultTagFunc:   cfg.DefaultTagFunc,
 	}, nil
 }
 
@@ -57,6 +241,42 @@ func (db *DB) Table(name string) *Table {
 	}
 }
 
+// TableWithOptions returns a Table handle for the specified sheet name
+// configured with opts.
+func (db *DB) TableWithOptions(name string, opts TableOptions) *Table {
+	return &Table{
+		db:   db,
+		name: name,
+		opts: opts,
+	}
+}
+
+// Tables fetches several sheets in a single batch request and returns a
+// handle for each, in order. Queries on these handles (Get, First, Count)
+// read the data fetched here instead of calling the API again, so they
+// don't see later changes; writes, and the reads they do, still go to the
+// API. Use db.Table for a handle that reads fresh data.
+func (db *DB) Tables(ctx context.Context, names ...string) ([]*Table, error) {
+	results, err := db.client.BatchRead(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tables: %w", err)
+	}
+
+	tables := make([]*Table, len(names))
+	for i, name := range names {
+		data := results[name]
+		if data == nil {
+			data = [][]interface{}{}
+		}
+		tables[i] = &Table{
+			db:       db,
+			name:     name,
+			snapshot: data,
+		}
+	}
+	return tables, nil
+}
+
 // Close releases any resources held by the database.
 func (db *DB) Close() error {
 	return nil
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
 )
 
 func TestNew(t *testing.T) {
@@ -12,6 +16,8 @@ func TestNew(t *testing.T) {
 		cfg           Config
 		wantErr       bool
 		expectedError string
+		wantIs        error
+		wantField     string
 	}{
 		{
 			name: "missing spreadsheet id",
@@ -21,6 +27,8 @@ func TestNew(t *testing.T) {
 			},
 			wantErr:       true,
 			expectedError: "spreadsheet ID is required",
+			wantField:     "SpreadsheetID",
+			wantIs:        ErrSpreadsheetIDRequired,
 		},
 		{
 			name: "missing credentials",
@@ -30,6 +38,8 @@ func TestNew(t *testing.T) {
 			},
 			wantErr:       true,
 			expectedError: "credentials are required",
+			wantField:     "Credentials",
+			wantIs:        ErrCredentialsRequired,
 		},
 		{
 			name: "empty credentials",
@@ -39,6 +49,48 @@ func TestNew(t *testing.T) {
 			},
 			wantErr:       true,
 			expectedError: "credentials are required",
+			wantField:     "Credentials",
+			wantIs:        ErrCredentialsRequired,
+		},
+		{
+			name: "token source",
+			cfg: Config{
+				SpreadsheetID: "test-id",
+				TokenSource:   oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+			},
+		},
+		{
+			name: "credentials and token source",
+			cfg: Config{
+				SpreadsheetID: "test-id",
+				Credentials:   []byte(`{"type":"service_account"}`),
+				TokenSource:   oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+			},
+			wantErr:       true,
+			expectedError: "only one of credentials and token source may be set",
+			wantField:     "TokenSource",
+		},
+		{
+			name: "invalid value input option",
+			cfg: Config{
+				SpreadsheetID:    "test-id",
+				Credentials:      []byte(`{"type":"service_account"}`),
+				ValueInputOption: "FORMULA",
+			},
+			wantErr:       true,
+			expectedError: `invalid value input option "FORMULA": must be RAW or USER_ENTERED`,
+			wantField:     "ValueInputOption",
+		},
+		{
+			name: "invalid value render option",
+			cfg: Config{
+				SpreadsheetID:     "test-id",
+				Credentials:       []byte(`{"type":"service_account"}`),
+				ValueRenderOption: "RAW",
+			},
+			wantErr:       true,
+			expectedError: `invalid value render option "RAW": must be FORMATTED_VALUE, UNFORMATTED_VALUE or FORMULA`,
+			wantField:     "ValueRenderOption",
 		},
 	}
 
@@ -54,6 +106,15 @@ func TestNew(t *testing.T) {
 				if tt.expectedError != "" && err.Error() != tt.expectedError {
 					t.Errorf("New() error = %v, want %v", err.Error(), tt.expectedError)
 				}
+				if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+					t.Errorf("New() error = %v, want errors.Is %v", err, tt.wantIs)
+				}
+				var cfgErr *ConfigError
+				if !errors.As(err, &cfgErr) {
+					t.Errorf("New() error = %T, want *ConfigError", err)
+				} else if cfgErr.Field != tt.wantField {
+					t.Errorf("ConfigError.Field = %q, want %q", cfgErr.Field, tt.wantField)
+				}
 				return
 			}
 
@@ -101,6 +162,63 @@ func TestDB_Table(t *testing.T) {
 	}
 }
 
+func TestDB_Tables(t *testing.T) {
+	ctx := context.Background()
+	mockClient := &MockSheetsClient{
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users": {
+					{"ID", "Name", "Email", "Age"},
+					{1.0, "Alice", "alice@test.com", 30.0},
+					{2.0, "Bob", "bob@test.com", 25.0},
+				},
+				"Orders": {
+					{"ID", "Item"},
+					{10.0, "pen"},
+				},
+			}, nil
+		},
+	}
+	db := &DB{client: mockClient}
+
+	tables, err := db.Tables(ctx, "Users", "Orders", "Empty")
+	if err != nil {
+		t.Fatalf("Tables() error = %v", err)
+	}
+	if len(tables) != 3 {
+		t.Fatalf("Tables() returned %d tables, want 3", len(tables))
+	}
+
+	if len(mockClient.BatchReadCalls) != 1 {
+		t.Fatalf("Tables() expected 1 batch read, got %d", len(mockClient.BatchReadCalls))
+	}
+	if got := mockClient.BatchReadCalls[0].Ranges; len(got) != 3 || got[0] != "Users" || got[1] != "Orders" {
+		t.Errorf("Tables() ranges = %v", got)
+	}
+
+	var users []TestUser
+	if err := tables[0].Query().Where("Age", ">", 26).Get(ctx, &users); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "Alice" {
+		t.Errorf("Get() = %+v, want Alice", users)
+	}
+
+	count, err := tables[1].Query().Count(ctx)
+	if err != nil || count != 1 {
+		t.Errorf("Count() = %d, %v, want 1", count, err)
+	}
+
+	count, err = tables[2].Query().Count(ctx)
+	if err != nil || count != 0 {
+		t.Errorf("Count() on empty sheet = %d, %v, want 0", count, err)
+	}
+
+	if len(mockClient.ReadCalls) != 0 {
+		t.Errorf("queries on prefetched tables should not read, got %d reads", len(mockClient.ReadCalls))
+	}
+}
+
 func TestDB_Close(t *testing.T) {
 	db := &DB{
 		spreadsheetID: "test-id",
@@ -113,6 +231,22 @@ func TestDB_Close(t *testing.T) {
 	}
 }
 
+func TestNew_DefaultCredentials(t *testing.T) {
+	original := newSheetsService
+	defer func() { newSheetsService = original }()
+	newSheetsService = func(ctx context.Context, opts ...option.ClientOption) (*sheets.Service, error) {
+		return &sheets.Service{}, nil
+	}
+
+	db, err := New(Config{SpreadsheetID: "test-id", UseDefaultCredentials: true})
+	if err != nil {
+		t.Fatalf("New() unexpected error = %v", err)
+	}
+	if db == nil {
+		t.Error("New() returned nil DB")
+	}
+}
+
 func TestNew_WithInvalidCredentials(t *testing.T) {
 	cfg := Config{
 		SpreadsheetID: "test-id",
@@ -0,0 +1,126 @@
+package quire
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrSpreadsheetIDRequired is returned by New when Config.SpreadsheetID is
+// empty.
+var ErrSpreadsheetIDRequired = errors.New("spreadsheet ID is required")
+
+// ErrCredentialsRequired is returned by New when the Config sets none of
+// Credentials, TokenSource and UseDefaultCredentials.
+var ErrCredentialsRequired = errors.New("credentials are required")
+
+// ConfigError is returned by New when the Config is missing a required
+// field or sets one to an invalid value. Field names the Config field at
+// fault, e.g. "SpreadsheetID" or "ValueInputOption":
+//
+//	var cfgErr *quire.ConfigError
+//	if errors.As(err, &cfgErr) && cfgErr.Field == "Credentials" {
+//		// prompt for a key file
+//	}
+//
+// Missing fields also wrap ErrSpreadsheetIDRequired or
+// ErrCredentialsRequired, so errors.Is works for those too.
+type ConfigError struct {
+	Field string
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ErrSheetNotFound is returned by operations that need a sheet's metadata,
+// such as Size, deletes and automatic sheet growth, when the spreadsheet
+// has no sheet of that name.
+var ErrSheetNotFound = errors.New("sheet not found")
+
+// ErrHeaderChanged is returned by queries on a table with
+// TableOptions.DetectHeaderChanges when the header row differs from the one
+// seen by the previous query on the same Table handle.
+var ErrHeaderChanged = errors.New("header row changed")
+
+// ErrTableNotAllowed is returned by writes to a sheet that isn't listed in
+// Config.AllowedTables.
+var ErrTableNotAllowed = errors.New("table not allowed")
+
+// ErrNoRows is returned by Query.First when no row matches the query, and
+// by Find, UpdateByKey and DeleteByKey when no row has the key.
+var ErrNoRows = errors.New("no rows in result set")
+
+// QuotaExceededError reports that the Sheets API rejected a call with 429
+// Too Many Requests. RetryAfter is the server's hint for how long to wait,
+// or zero if the response didn't include one.
+type QuotaExceededError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("quota exceeded, retry after %s: %v", e.RetryAfter, e.Err)
+	}
+	return fmt.Sprintf("quota exceeded: %v", e.Err)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return e.Err
+}
+
+// APIError reports a call the Sheets API rejected, with the HTTP status so
+// callers can branch on the kind of failure:
+//
+//	var apiErr *quire.APIError
+//	if errors.As(err, &apiErr) && apiErr.IsNotFound() {
+//		// the spreadsheet or range doesn't exist
+//	}
+//
+// It wraps the underlying *googleapi.Error, and for 429 responses a
+// QuotaExceededError, so errors.As finds those too.
+type APIError struct {
+	// StatusCode is the HTTP status of the response, e.g. 403 or 429.
+	StatusCode int
+	// Reason is the API's machine-readable reason, such as
+	// "rateLimitExceeded" or "notFound", or empty if it gave none.
+	Reason string
+	// Range is the A1 range or sheet name the call addressed, or empty
+	// for calls on the whole spreadsheet.
+	Range string
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	return e.err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// IsRateLimited reports whether the call was rejected for exceeding a
+// quota. Such calls can be retried after a delay.
+func (e *APIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.Reason == "rateLimitExceeded" || e.Reason == "userRateLimitExceeded"
+}
+
+// IsNotFound reports whether the spreadsheet or range doesn't exist.
+func (e *APIError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsPermissionDenied reports whether the credentials lack access to the
+// spreadsheet, or the scope for the operation.
+func (e *APIError) IsPermissionDenied() bool {
+	return e.StatusCode == http.StatusForbidden && !e.IsRateLimited()
+}
@@ -0,0 +1,116 @@
+package quire
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ExportCSV writes the table, header row included, to w as CSV. Short
+// rows are padded with empty cells to the width of the widest row.
+func (t *Table) ExportCSV(ctx context.Context, w io.Writer) error {
+	data, err := t.queryData(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read data: %w", err)
+	}
+	return writeCSV(w, data)
+}
+
+func writeCSV(w io.Writer, data [][]interface{}) error {
+	width := 0
+	for _, row := range data {
+		width = max(width, len(row))
+	}
+
+	cw := csv.NewWriter(w)
+	for _, row := range data {
+		record := make([]string, width)
+		for i, cell := range row {
+			if cell != nil {
+				record[i] = formatCell(cell)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// ExportAll backs up every sheet of the spreadsheet to dir, one
+// "<sheet>.csv" file per sheet, creating dir if needed. All sheets are read
+// in a single request. Characters that aren't allowed in file names are
+// replaced with "_", and a numeric suffix keeps names that collide after
+// that distinct.
+func (db *DB) ExportAll(ctx context.Context, dir string) error {
+	names, err := db.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	tables, err := db.Tables(ctx, names...)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	used := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		base := csvFileName(t.name)
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = base + "_" + strconv.Itoa(n)
+		}
+		used[strings.ToLower(name)] = true
+
+		if err := exportFile(ctx, t, filepath.Join(dir, name+".csv")); err != nil {
+			return fmt.Errorf("failed to export sheet %s: %w", t.name, err)
+		}
+	}
+	return nil
+}
+
+func exportFile(ctx context.Context, t *Table, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := t.ExportCSV(ctx, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// csvFileName turns a sheet name into a file name without the extension,
+// replacing path separators, characters reserved on Windows and control
+// characters with "_".
+func csvFileName(sheet string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, sheet)
+
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "_"
+	}
+	return name
+}
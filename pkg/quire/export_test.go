@@ -0,0 +1,109 @@
+package quire
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestTable_ExportCSV(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Note"},
+				{1.0, "Alice", "says \"hi\", twice"},
+				{2.0, "Bob"},
+				{1000000.0, nil, "big"},
+			}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	table := (&DB{client: mock}).Table("Users")
+	if err := table.ExportCSV(context.Background(), &buf); err != nil {
+		t.Fatalf("ExportCSV() unexpected error = %v", err)
+	}
+
+	want := "ID,Name,Note\n" +
+		"1,Alice,\"says \"\"hi\"\", twice\"\n" +
+		"2,Bob,\n" +
+		"1000000,,big\n"
+	if buf.String() != want {
+		t.Errorf("ExportCSV() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDB_ExportAll(t *testing.T) {
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{{Title: "Users"}, {Title: "Q1/Q2: Sales"}, {Title: "Q1_Q2_ Sales"}, {Title: "Empty"}}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users":        {{"ID", "Name"}, {1.0, "Alice"}},
+				"Q1/Q2: Sales": {{"Region", "Total"}, {"North", 12.5}},
+				"Q1_Q2_ Sales": {{"Region"}},
+			}, nil
+		},
+	}
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	if err := (&DB{client: mock}).ExportAll(context.Background(), dir); err != nil {
+		t.Fatalf("ExportAll() unexpected error = %v", err)
+	}
+
+	if len(mock.BatchReadCalls) != 1 || len(mock.ReadCalls) != 0 {
+		t.Errorf("ExportAll() made %d batch reads and %d reads, want a single batch read",
+			len(mock.BatchReadCalls), len(mock.ReadCalls))
+	}
+
+	want := map[string]string{
+		"Users.csv":          "ID,Name\n1,Alice\n",
+		"Q1_Q2_ Sales.csv":   "Region,Total\nNorth,12.5\n",
+		"Q1_Q2_ Sales_2.csv": "Region\n",
+		"Empty.csv":          "",
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() unexpected error = %v", err)
+	}
+	var files []string
+	for _, e := range entries {
+		files = append(files, e.Name())
+	}
+	sort.Strings(files)
+	if len(files) != len(want) {
+		t.Errorf("ExportAll() wrote %v, want %d files", files, len(want))
+	}
+
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("ExportAll() missing %s: %v", name, err)
+			continue
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestCSVFileName(t *testing.T) {
+	tests := map[string]string{
+		"Users":        "Users",
+		"Q1/Q2":        "Q1_Q2",
+		`a\b:c*d?"e"`:  "a_b_c_d__e_",
+		"<tab>|\tname": "_tab___name",
+		"trailing. ":   "trailing",
+		"..":           "_",
+	}
+	for sheet, want := range tests {
+		if got := csvFileName(sheet); got != want {
+			t.Errorf("csvFileName(%q) = %q, want %q", sheet, got, want)
+		}
+	}
+}
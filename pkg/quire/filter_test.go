@@ -1,7 +1,13 @@
 package quire
 
 import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMatchesOperator(t *testing.T) {
@@ -32,6 +38,33 @@ func TestMatchesOperator(t *testing.T) {
 		{"like true", "Hello World", "like", "hello", true},
 		{"like case insensitive", "HELLO", "like", "hello", true},
 		{"unknown operator", "test", "unknown", "test", false},
+		{"in string slice", "trial", "in", []string{"active", "trial"}, true},
+		{"in interface slice", 2.0, "in", []interface{}{1, 2, 3}, true},
+		{"in no match", "deleted", "in", []string{"active", "trial"}, false},
+		{"equal large float", "1000000", "=", 1e6, true},
+		{"equal large float cell", 1e6, "=", "1000000", true},
+		{"not equal large float", "1000000", "!=", 1e6, false},
+		{"in large float", "2500000", "in", []float64{1e6, 2.5e6}, true},
+		{"not in large float", "1000000", "not in", []interface{}{1e6}, false},
+		{"in empty slice", "active", "in", []string{}, false},
+		{"in non-slice", "active", "in", "active", false},
+		{"not in match", "deleted", "not in", []string{"active", "trial"}, true},
+		{"not in no match", "active", "not in", []string{"active", "trial"}, false},
+		{"not in empty slice", "active", "not in", []string{}, true},
+		{"not in non-slice", "active", "not in", "other", false},
+		{"numeric greater", "100", ">n", 20, true},
+		{"numeric vs string order", "100", ">n", "20", true},
+		{"heuristic string order", "abc", ">", 20, true},
+		{"numeric text never matches", "abc", ">n", 20, false},
+		{"numeric text not less", "abc", "<=n", 20, false},
+		{"numeric text not unequal", "abc", "!=n", 20, false},
+		{"numeric greater or equal", 20.0, ">=n", "20", true},
+		{"numeric less", " 7.5 ", "<n", 10, true},
+		{"numeric less or equal false", "12", "<=n", 10, false},
+		{"numeric equal", "1e2", "=n", 100, true},
+		{"numeric not equal", "100.5", "!=n", 100, true},
+		{"numeric blank", "", "<n", 10, false},
+		{"numeric value not a number", "5", "<n", "ten", false},
 	}
 
 	for _, tt := range tests {
@@ -157,6 +190,46 @@ func TestQuery_MatchesFilters(t *testing.T) {
 	}
 }
 
+func TestQuery_MatchesFilters_Empty(t *testing.T) {
+	headers := []interface{}{"ID", "Email", "Notes", "Meta"}
+	rows := map[string][]interface{}{
+		"present":      {1.0, "a@test.com", "call back", `{"tag":"vip"}`},
+		"empty string": {2.0, "", "  ", `{"tag":""}`},
+		"nil":          {3.0, nil, nil, nil},
+		"missing":      {4.0},
+	}
+
+	tests := []struct {
+		column   string
+		operator string
+		matches  []string
+	}{
+		{"Email", "empty", []string{"empty string", "nil", "missing"}},
+		{"Email", "isnull", []string{"empty string", "nil", "missing"}},
+		{"Email", "notempty", []string{"present"}},
+		{"Email", "notnull", []string{"present"}},
+		{"Notes", "empty", []string{"empty string", "nil", "missing"}},
+		{"Notes", "notempty", []string{"present"}},
+		{"Meta->tag", "empty", []string{"empty string", "nil", "missing"}},
+		{"Meta->tag", "notempty", []string{"present"}},
+		{"Phone", "empty", nil},
+		{"Phone", "notempty", nil},
+	}
+
+	for _, tt := range tests {
+		q := &Query{filters: []Filter{{Column: tt.column, Operator: tt.operator}}}
+		var got []string
+		for _, name := range []string{"present", "empty string", "nil", "missing"} {
+			if q.matchesFilters(rows[name], headers) {
+				got = append(got, name)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.matches) {
+			t.Errorf("Where(%q, %q) matched %v, want %v", tt.column, tt.operator, got, tt.matches)
+		}
+	}
+}
+
 func TestQuery_ApplyFilters(t *testing.T) {
 	q := &Query{
 		filters: []Filter{
@@ -249,6 +322,44 @@ func TestQuery_ApplyLimit_GreaterThanLength(t *testing.T) {
 	}
 }
 
+func TestQuery_LimitPercent(t *testing.T) {
+	rows := make([][]interface{}, 11)
+	for i := range rows {
+		rows[i] = []interface{}{float64(i)}
+	}
+
+	tests := []struct {
+		percent float64
+		limit   int
+		want    int
+	}{
+		{percent: 25, want: 3},
+		{percent: 50, want: 6},
+		{percent: 1, want: 1},
+		{percent: 100, want: 11},
+		{percent: 250, want: 11},
+		{percent: 0, want: 11},
+		{percent: -10, want: 11},
+		{percent: 50, limit: 4, want: 4},
+		{percent: 10, limit: 4, want: 2},
+	}
+
+	for _, tt := range tests {
+		q := (&Query{}).LimitPercent(tt.percent).Limit(tt.limit)
+		result := q.applyLimit(rows)
+		if len(result) != tt.want {
+			t.Errorf("LimitPercent(%v) with Limit(%d) kept %d of 11 rows, want %d", tt.percent, tt.limit, len(result), tt.want)
+		}
+		if len(result) > 0 && result[0][0] != 0.0 {
+			t.Errorf("LimitPercent(%v) did not keep the first rows: %v", tt.percent, result)
+		}
+	}
+
+	if got := (&Query{}).LimitPercent(50).applyLimit(nil); len(got) != 0 {
+		t.Errorf("LimitPercent(50) on no rows = %v, want none", got)
+	}
+}
+
 func TestQuery_Chaining(t *testing.T) {
 	db := &DB{client: &MockSheetsClient{}}
 	table := &Table{db: db, name: "Users"}
@@ -271,3 +382,673 @@ func TestQuery_Chaining(t *testing.T) {
 		t.Errorf("Chained OrderBy() should set orderBy to Name, got %s", query.orderBy)
 	}
 }
+
+func TestQuery_MatchesFilters_JSONPath(t *testing.T) {
+	headers := []interface{}{"ID", "Meta"}
+	rows := [][]interface{}{
+		{1.0, `{"role":"admin","profile":{"level":3}}`},
+		{2.0, `{"role":"viewer","profile":{"level":1}}`},
+		{3.0, "not json"},
+		{4.0},
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []float64
+	}{
+		{"top-level key", Filter{Column: "Meta->role", Operator: "=", Value: "admin"}, []float64{1}},
+		{"dotted key", Filter{Column: "Meta->profile.level", Operator: ">=", Value: 2}, []float64{1}},
+		{"missing key", Filter{Column: "Meta->team", Operator: "=", Value: "x"}, nil},
+		{"unknown column", Filter{Column: "Other->role", Operator: "=", Value: "admin"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Query{filters: []Filter{tt.filter}}
+
+			var got []float64
+			for _, row := range q.applyFilters(rows, headers) {
+				got = append(got, row[0].(float64))
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("applyFilters() IDs = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQuery_ApplySort(t *testing.T) {
+	headers := []interface{}{"Name", "Score"}
+	rows := [][]interface{}{
+		{"Alice", 30.0},
+		{"Bob", ""},
+		{"Charlie", 10.0},
+		{"Diana"},
+		{"Eve", 20.0},
+	}
+
+	tests := []struct {
+		name       string
+		descending bool
+		nullsLast  bool
+		expected   []string
+	}{
+		{"ascending", false, false, []string{"Bob", "Diana", "Charlie", "Eve", "Alice"}},
+		{"descending", true, false, []string{"Alice", "Eve", "Charlie", "Bob", "Diana"}},
+		{"ascending nulls last", false, true, []string{"Charlie", "Eve", "Alice", "Bob", "Diana"}},
+		{"descending nulls last", true, true, []string{"Alice", "Eve", "Charlie", "Bob", "Diana"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Query{}
+			q.OrderBy("Score", tt.descending).NullsLast(tt.nullsLast)
+
+			var got []string
+			for _, row := range q.applySort(rows, headers) {
+				got = append(got, row[0].(string))
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("applySort() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQuery_ApplySort_UnknownColumn(t *testing.T) {
+	q := &Query{}
+	q.OrderBy("Missing", false)
+
+	rows := [][]interface{}{{"b"}, {"a"}}
+	result := q.applySort(rows, []interface{}{"Name"})
+
+	if result[0][0] != "b" || result[1][0] != "a" {
+		t.Errorf("applySort() with unknown column should keep order, got %v", result)
+	}
+}
+
+func TestQuery_OrWhere(t *testing.T) {
+	headers := []interface{}{"Name", "Status", "Age"}
+	rows := [][]interface{}{
+		{"Alice", "active", 30.0},
+		{"Bob", "pending", 17.0},
+		{"Charlie", "pending", 40.0},
+		{"Diana", "deleted", 50.0},
+	}
+
+	tests := []struct {
+		name     string
+		build    func(q *Query)
+		expected []string
+	}{
+		{
+			name: "single OR group",
+			build: func(q *Query) {
+				q.Where("Status", "=", "active").OrWhere("Status", "=", "pending")
+			},
+			expected: []string{"Alice", "Bob", "Charlie"},
+		},
+		{
+			name: "OR group AND-ed with condition",
+			build: func(q *Query) {
+				q.Where("Status", "=", "active").OrWhere("Status", "=", "pending").Where("Age", ">=", 18)
+			},
+			expected: []string{"Alice", "Charlie"},
+		},
+		{
+			name: "condition AND-ed with OR group",
+			build: func(q *Query) {
+				q.Where("Age", ">=", 18).Where("Status", "=", "pending").OrWhere("Status", "=", "deleted")
+			},
+			expected: []string{"Charlie", "Diana"},
+		},
+		{
+			name: "leading OrWhere",
+			build: func(q *Query) {
+				q.OrWhere("Name", "=", "Bob").OrWhere("Name", "=", "Diana")
+			},
+			expected: []string{"Bob", "Diana"},
+		},
+		{
+			name: "AND only",
+			build: func(q *Query) {
+				q.Where("Status", "=", "pending").Where("Age", ">=", 18)
+			},
+			expected: []string{"Charlie"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Query{}
+			tt.build(q)
+
+			var got []string
+			for _, row := range q.applyFilters(rows, headers) {
+				got = append(got, row[0].(string))
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("applyFilters() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQuery_WhereDateBetween(t *testing.T) {
+	headers := []interface{}{"Name", "Joined"}
+	rows := [][]interface{}{
+		{"before", "2023-12-31"},
+		{"from", "2024-01-01"},
+		{"inside", "2024-01-15 09:30:00"},
+		{"slashes", "1/20/2024"},
+		{"to", "2024-01-31T00:00:00Z"},
+		{"after", "2024-02-01"},
+		{"invalid", "soon"},
+		{"blank", ""},
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	q := &Query{}
+	q.WhereDateBetween("Joined", from, to)
+
+	var got []string
+	for _, row := range q.applyFilters(rows, headers) {
+		got = append(got, row[0].(string))
+	}
+
+	want := []string{"from", "inside", "slashes", "to"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WhereDateBetween() matched %v, want %v", got, want)
+	}
+}
+
+func TestQuery_WhereDateBetween_Location(t *testing.T) {
+	headers := []interface{}{"Name", "Joined"}
+	rows := [][]interface{}{
+		{"before", "2023-12-31"},
+		{"from", "2024-01-01"},
+		{"to", "2024-01-31"},
+		{"after", "2024-02-01"},
+	}
+
+	est := time.FixedZone("EST", -5*60*60)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, est)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, est)
+
+	q := &Query{}
+	q.WhereDateBetween("Joined", from, to)
+
+	var got []string
+	for _, row := range q.applyFilters(rows, headers) {
+		got = append(got, row[0].(string))
+	}
+
+	want := []string{"from", "to"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WhereDateBetween() matched %v, want %v", got, want)
+	}
+
+	if !matchesOperator("2024-01-01", "=", from) {
+		t.Errorf("matchesOperator(2024-01-01 = %v) = false, want true", from)
+	}
+}
+
+func TestQuery_CaseInsensitive(t *testing.T) {
+	headers := []interface{}{"Name"}
+	row := []interface{}{"Alice"}
+
+	tests := []struct {
+		operator string
+		value    interface{}
+		exact    bool
+		folded   bool
+	}{
+		{"=", "alice", false, true},
+		{"=", "Alice", true, true},
+		{"!=", "ALICE", true, false},
+		{"in", []string{"bob", "alice"}, false, true},
+		{"not in", []string{"ALICE"}, true, false},
+		{"contains", "LIC", true, true},
+	}
+
+	for _, tt := range tests {
+		for _, fold := range []bool{false, true} {
+			q := &Query{}
+			q.Where("Name", tt.operator, tt.value).CaseInsensitive(fold)
+
+			want := tt.exact
+			if fold {
+				want = tt.folded
+			}
+			if got := q.matchesFilters(row, headers); got != want {
+				t.Errorf("Alice %s %v (CaseInsensitive=%v) = %v, want %v", tt.operator, tt.value, fold, got, want)
+			}
+		}
+	}
+}
+
+func TestQuery_WhereNow(t *testing.T) {
+	headers := []interface{}{"Name", "ExpiresAt"}
+	rows := [][]interface{}{
+		{"expired", time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)},
+		{"valid", time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)},
+		{"never", ""},
+	}
+
+	tests := []struct {
+		operator string
+		expected []string
+	}{
+		{"<", []string{"expired"}},
+		{">=", []string{"valid"}},
+	}
+
+	for _, tt := range tests {
+		q := &Query{}
+		q.Where("ExpiresAt", tt.operator, Now())
+
+		var got []string
+		for _, row := range q.applyFilters(rows, headers) {
+			got = append(got, row[0].(string))
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ExpiresAt %s Now() matched %v, want %v", tt.operator, got, tt.expected)
+		}
+
+		// The placeholder itself is kept for the next run.
+		if _, ok := q.filters[0].Value.(nowPlaceholder); !ok {
+			t.Errorf("filter value = %v, want Now() placeholder preserved", q.filters[0].Value)
+		}
+	}
+}
+
+func TestMatchesOperator_Time(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		cell     interface{}
+		op       string
+		expected bool
+	}{
+		{"2024-05-31", "<", true},
+		{"2024-06-01", "=", true},
+		{"2024-06-01T00:00:00Z", "<=", true},
+		{"2024-06-02", ">", true},
+		{"2024-06-02", "!=", true},
+		{"not a date", "<", false},
+		{"not a date", "!=", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesOperator(tt.cell, tt.op, day); got != tt.expected {
+			t.Errorf("matchesOperator(%v %s %v) = %v, want %v", tt.cell, tt.op, day, got, tt.expected)
+		}
+	}
+}
+
+func TestQuery_FilterTrace(t *testing.T) {
+	headers := []interface{}{"Name", "Age", "Active"}
+	rows := [][]interface{}{
+		{"Alice", 30.0, true},
+		{"Bob", 17.0, true},
+		{"Carol", 40.0, false},
+	}
+
+	type call struct {
+		row     int
+		column  string
+		matched bool
+	}
+	var calls []call
+	db := &DB{filterTrace: func(rowIndex int, filter Filter, matched bool) {
+		calls = append(calls, call{rowIndex, filter.Column, matched})
+	}}
+
+	q := db.Table("Users").Query().
+		Where("Age", ">=", 18).
+		Where("Active", "=", true)
+
+	got := q.applyFilters(rows, headers)
+	if len(got) != 1 || got[0][0] != "Alice" {
+		t.Fatalf("applyFilters() = %v, want only Alice", got)
+	}
+
+	// Bob is rejected by Age, so Active is never evaluated for him.
+	want := []call{
+		{0, "Age", true},
+		{0, "Active", true},
+		{1, "Age", false},
+		{2, "Age", true},
+		{2, "Active", false},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("trace calls = %v, want %v", calls, want)
+	}
+}
+
+func TestQuery_WhereExistsIn(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			switch range_ {
+			case "Users":
+				return [][]interface{}{
+					{"ID", "Name", "Email"},
+					{1.0, "Alice", "ALICE@test.com"},
+					{2.0, "Bob", "bob@test.com"},
+					{3.0, "Carol", ""},
+					{4.0, "Dave", "dave@test.com"},
+				}, nil
+			case "Orders":
+				return [][]interface{}{
+					{"OrderID", "UserID", "Email"},
+					{100.0, 1.0, "alice@test.com"},
+					{101.0, 4.0, ""},
+					{102.0, 1.0, "nobody@test.com"},
+					{103.0, 9.0},
+				}, nil
+			case "Archive":
+				return [][]interface{}{{"OrderID"}}, nil
+			}
+			return nil, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	names := func(users []TestUser) []string {
+		var out []string
+		for _, u := range users {
+			out = append(out, u.Name)
+		}
+		return out
+	}
+
+	var users []TestUser
+	if err := table.Query().WhereExistsIn("ID", "Orders", "UserID").Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if got := names(users); !reflect.DeepEqual(got, []string{"Alice", "Dave"}) {
+		t.Errorf("WhereExistsIn(ID) = %v, want [Alice Dave]", got)
+	}
+
+	// Carol's blank email must not match the blank email on order 101, and
+	// Alice's only matches when case is ignored.
+	users = nil
+	if err := table.Query().WhereExistsIn("Email", "Orders", "Email").Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("WhereExistsIn(Email) = %v, want no matches", names(users))
+	}
+	count, err := table.Query().CaseInsensitive(true).WhereExistsIn("Email", "Orders", "Email").Count(ctx)
+	if err != nil {
+		t.Fatalf("Count() unexpected error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("WhereExistsIn(Email) with CaseInsensitive counted %d, want 1", count)
+	}
+
+	users = nil
+	err = table.Query().
+		Where("Name", "!=", "Alice").
+		WhereExistsIn("ID", "Orders", "UserID").
+		Get(ctx, &users)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if got := names(users); !reflect.DeepEqual(got, []string{"Dave"}) {
+		t.Errorf("Where + WhereExistsIn = %v, want [Dave]", got)
+	}
+
+	reads := 0
+	for _, r := range mock.ReadCalls {
+		if r.Range_ == "Orders" {
+			reads++
+		}
+	}
+	if reads != 4 {
+		t.Errorf("Orders read %d times for 4 queries, want once per query", reads)
+	}
+
+	if err := table.Query().WhereExistsIn("ID", "Archive", "UserID").Get(ctx, &users); err == nil {
+		t.Error("Get() expected error for a subquery column the other sheet lacks")
+	}
+}
+
+func TestQuery_WhereExistsIn_LenientHeaders(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			if range_ == "Orders" {
+				return [][]interface{}{
+					{"OrderID", " user id "},
+					{100.0, 2.0},
+				}, nil
+			}
+			return [][]interface{}{
+				{"ID", "Name"},
+				{1.0, "Alice"},
+				{2.0, "Bob"},
+			}, nil
+		},
+	}
+	db := &DB{client: mock, lenientHeaders: true}
+
+	var users []TestUser
+	if err := db.Table("Users").Query().WhereExistsIn("ID", "Orders", "User ID").Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "Bob" {
+		t.Errorf("WhereExistsIn() = %+v, want only Bob", users)
+	}
+
+	rows, err := db.Table("Orders").ColumnWithRows(ctx, "USER ID")
+	if err != nil {
+		t.Fatalf("ColumnWithRows() unexpected error = %v", err)
+	}
+	if want := map[int]string{2: "2"}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("ColumnWithRows() = %v, want %v", rows, want)
+	}
+}
+
+func TestQuery_WhereRegex(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email"},
+				{1.0, "Alice", "alice@example.com"},
+				{2.0, "Bob", "bob@TEST.org"},
+				{3.0, "alfred", "alfred@example.com"},
+				{4.0, "Dave"},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	tests := []struct {
+		name    string
+		query   *Query
+		wantIDs []int
+	}{
+		{"matching", table.Query().Where("Email", "regex", `@example\.com$`), []int{1, 3}},
+		{"matches alias", table.Query().Where("Name", "matches", `^[A-Z]`), []int{1, 2, 4}},
+		{"non-matching", table.Query().Where("Email", "regex", `\.net$`), nil},
+		{"case-insensitive flag", table.Query().Where("Name", "regex", `(?i)^al`), []int{1, 3}},
+		{"case-sensitive by default", table.Query().Where("Email", "regex", `test\.org`), nil},
+		{"CaseInsensitive", table.Query().CaseInsensitive(true).Where("Email", "regex", `test\.org`), []int{2}},
+		{"numbers as text", table.Query().Where("ID", "regex", `^[23]$`), []int{2, 3}},
+		{"compiled", table.Query().Where("Name", "regex", regexp.MustCompile(`e$`)), []int{1, 4}},
+	}
+	for _, tt := range tests {
+		var users []TestUser
+		if err := tt.query.Get(ctx, &users); err != nil {
+			t.Fatalf("%s: Get() unexpected error = %v", tt.name, err)
+		}
+		var ids []int
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.wantIDs) {
+			t.Errorf("%s: Get() = %v, want %v", tt.name, ids, tt.wantIDs)
+		}
+	}
+}
+
+func TestQuery_WhereRegex_InvalidPattern(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name"},
+				{1.0, "Alice"},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	var users []TestUser
+	if err := table.Query().Where("Name", "regex", `(unclosed`).Get(ctx, &users); err == nil {
+		t.Error("Get() expected error for an invalid pattern")
+	}
+	if _, err := table.Query().Where("Name", "regex", `[z-a]`).Count(ctx); err == nil {
+		t.Error("Count() expected error for an invalid pattern")
+	}
+
+	mock.Reset()
+	if err := table.DeleteWhere(ctx, "Name", "matches", `*`); err == nil {
+		t.Error("DeleteWhere() expected error for an invalid pattern")
+	}
+	if len(mock.ReadCalls) != 0 {
+		t.Errorf("DeleteWhere() read the sheet %d times despite an invalid pattern", len(mock.ReadCalls))
+	}
+}
+
+func TestQuery_WhereNotExistsIn(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			switch range_ {
+			case "Products":
+				return [][]interface{}{
+					{"SKU", "Name", "Price"},
+					{"A-1", "Anvil", 99.5},
+					{"B-2", "Bucket", 5.0},
+					{"c-3", "Chisel", 12.0},
+					{"D-4", "Drill", 150.0},
+					{nil, "Unlisted", 1.0},
+				}, nil
+			case "Orders":
+				return [][]interface{}{
+					{"OrderID", "SKU"},
+					{1.0, "B-2"},
+					{2.0, "C-3"},
+					{3.0, "B-2"},
+					{4.0, "Z-9"},
+				}, nil
+			}
+			return nil, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Products")
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{"never ordered", table.Query().WhereNotExistsIn("SKU", "Orders", "SKU"), []string{"A-1", "c-3", "D-4"}},
+		{"CaseInsensitive", table.Query().CaseInsensitive(true).WhereNotExistsIn("SKU", "Orders", "SKU"), []string{"A-1", "D-4"}},
+		{"ordered", table.Query().WhereExistsIn("SKU", "Orders", "SKU"), []string{"B-2"}},
+		{"combined", table.Query().WhereNotExistsIn("SKU", "Orders", "SKU").Where("Price", ">", 50), []string{"A-1", "D-4"}},
+	}
+	for _, tt := range tests {
+		var products []TestProduct
+		if err := tt.query.Get(ctx, &products); err != nil {
+			t.Fatalf("%s: Get() unexpected error = %v", tt.name, err)
+		}
+		var skus []string
+		for _, p := range products {
+			skus = append(skus, p.SKU)
+		}
+		if !reflect.DeepEqual(skus, tt.want) {
+			t.Errorf("%s: Get() = %v, want %v", tt.name, skus, tt.want)
+		}
+	}
+}
+
+// parseShorthand reads numbers like "950", "1.2K" and "3.4M".
+func parseShorthand(s string) (float64, error) {
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "K"):
+		mult, s = 1e3, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1e6, strings.TrimSuffix(s, "M")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f * mult, err
+}
+
+func TestQuery_ParseColumn(t *testing.T) {
+	type Creator struct {
+		Name      string `quire:"Name"`
+		Followers string `quire:"Followers"`
+	}
+
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Name", "Followers"},
+				{"ana", "1.2K"},
+				{"ben", "3.4M"},
+				{"cy", 950.0},
+				{"dee", "n/a"},
+				{"eve", "800K"},
+				{"fin"},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Creators")
+	query := func() *Query {
+		return table.Query().ParseColumn("Followers", parseShorthand)
+	}
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{"greater than shorthand", query().Where("Followers", ">", "1K"), []string{"ana", "ben", "eve"}},
+		{"greater than number", query().Where("Followers", ">=", 1200), []string{"ana", "ben", "eve"}},
+		{"less than", query().Where("Followers", "<", "1M"), []string{"ana", "cy", "eve"}},
+		{"equal", query().Where("Followers", "=", 1200), []string{"ana"}},
+		{"not equal skips unparseable", query().Where("Followers", "!=", "1.2K"), []string{"ben", "cy", "eve"}},
+		{"numeric operator", query().Where("Followers", ">n", "900"), []string{"ana", "ben", "cy", "eve"}},
+		{"range", query().Where("Followers", ">", "1K").Where("Followers", "<", "1M"), []string{"ana", "eve"}},
+		{"other operators see the text", query().Where("Followers", "contains", "k"), []string{"ana", "eve"}},
+		{"ordering", query().Where("Followers", ">", 0).OrderBy("Followers", true), []string{"ben", "eve", "ana", "cy"}},
+		{"text without a parser", table.Query().Where("Followers", ">", "1K"), []string{"ben", "cy", "dee", "eve"}},
+		{"parser removed", query().ParseColumn("Followers", nil).Where("Followers", "<n", 1000), []string{"cy"}},
+	}
+	for _, tt := range tests {
+		var creators []Creator
+		if err := tt.query.Get(ctx, &creators); err != nil {
+			t.Fatalf("%s: Get() unexpected error = %v", tt.name, err)
+		}
+		var names []string
+		for _, c := range creators {
+			names = append(names, c.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: Get() = %v, want %v", tt.name, names, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package quire
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Hyperlink is a cell holding a link, for struct fields of columns of
+// clickable links. It is written as a =HYPERLINK("url","text") formula,
+// which the sheet turns into a link only with Config.ValueInputOption set
+// to USER_ENTERED. The URL can only be read back with
+// Config.ValueRenderOption set to FORMULA; otherwise reads see just the
+// displayed text.
+type Hyperlink struct {
+	URL  string
+	Text string
+}
+
+var hyperlinkType = reflect.TypeOf(Hyperlink{})
+
+// Formula returns the HYPERLINK formula for h. The text is left out when
+// it is empty, so the sheet shows the URL.
+func (h Hyperlink) Formula() string {
+	if h.Text == "" {
+		return `=HYPERLINK(` + formulaString(h.URL) + `)`
+	}
+	return `=HYPERLINK(` + formulaString(h.URL) + `,` + formulaString(h.Text) + `)`
+}
+
+// formulaString quotes s as a formula string literal.
+func formulaString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// parseHyperlink reads a cell into a Hyperlink. A =HYPERLINK("url","text")
+// or =HYPERLINK("url") formula yields its arguments; any other cell is
+// taken as the link's text, and also as its URL if it looks like one.
+func parseHyperlink(s string) Hyperlink {
+	if args, ok := hyperlinkArgs(s); ok {
+		h := Hyperlink{URL: args[0], Text: args[0]}
+		if len(args) == 2 {
+			h.Text = args[1]
+		}
+		return h
+	}
+
+	h := Hyperlink{Text: s}
+	if strings.Contains(s, "://") || strings.HasPrefix(s, "mailto:") {
+		h.URL = s
+	}
+	return h
+}
+
+// hyperlinkArgs returns the one or two string literal arguments of a
+// HYPERLINK formula. Formulas with other kinds of arguments, such as cell
+// references, are not recognized.
+func hyperlinkArgs(s string) ([]string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToUpper(s), "=HYPERLINK(") || !strings.HasSuffix(s, ")") {
+		return nil, false
+	}
+	rest := strings.TrimSpace(s[len("=HYPERLINK(") : len(s)-1])
+
+	var args []string
+	for {
+		arg, tail, ok := cutFormulaString(rest)
+		if !ok {
+			return nil, false
+		}
+		args = append(args, arg)
+
+		tail = strings.TrimSpace(tail)
+		if tail == "" {
+			break
+		}
+		if tail[0] != ',' && tail[0] != ';' || len(args) == 2 {
+			return nil, false
+		}
+		rest = strings.TrimSpace(tail[1:])
+	}
+	return args, true
+}
+
+// cutFormulaString splits a leading "..." literal, with "" as an escaped
+// quote, off s.
+func cutFormulaString(s string) (lit, rest string, ok bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", false
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '"' {
+			b.WriteByte('"')
+			i++
+			continue
+		}
+		return b.String(), s[i+1:], true
+	}
+	return "", "", false
+}
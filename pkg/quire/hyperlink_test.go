@@ -0,0 +1,84 @@
+package quire
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHyperlink(t *testing.T) {
+	tests := []struct {
+		cell string
+		want Hyperlink
+	}{
+		{`=HYPERLINK("https://example.com","Example")`, Hyperlink{URL: "https://example.com", Text: "Example"}},
+		{`=hyperlink( "https://example.com" ; "Example" )`, Hyperlink{URL: "https://example.com", Text: "Example"}},
+		{`=HYPERLINK("https://example.com")`, Hyperlink{URL: "https://example.com", Text: "https://example.com"}},
+		{`=HYPERLINK("https://example.com/?q=""go""","Say ""hi""")`, Hyperlink{URL: `https://example.com/?q="go"`, Text: `Say "hi"`}},
+		{"https://example.com", Hyperlink{URL: "https://example.com", Text: "https://example.com"}},
+		{"Example", Hyperlink{Text: "Example"}},
+		{`=HYPERLINK(A1,"Example")`, Hyperlink{Text: `=HYPERLINK(A1,"Example")`}},
+		{`=HYPERLINK("a","b","c")`, Hyperlink{Text: `=HYPERLINK("a","b","c")`}},
+		{"", Hyperlink{}},
+	}
+	for _, tt := range tests {
+		if got := parseHyperlink(tt.cell); got != tt.want {
+			t.Errorf("parseHyperlink(%q) = %+v, want %+v", tt.cell, got, tt.want)
+		}
+	}
+}
+
+func TestHyperlink_Formula(t *testing.T) {
+	tests := []struct {
+		link Hyperlink
+		want string
+	}{
+		{Hyperlink{URL: "https://example.com", Text: "Example"}, `=HYPERLINK("https://example.com","Example")`},
+		{Hyperlink{URL: "https://example.com"}, `=HYPERLINK("https://example.com")`},
+		{Hyperlink{URL: `https://example.com/?q="go"`, Text: `Say "hi"`}, `=HYPERLINK("https://example.com/?q=""go""","Say ""hi""")`},
+	}
+	for _, tt := range tests {
+		if got := tt.link.Formula(); got != tt.want {
+			t.Errorf("%+v.Formula() = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
+
+type Bookmark struct {
+	Name string     `quire:"Name"`
+	Link Hyperlink  `quire:"Link"`
+	Docs *Hyperlink `quire:"Docs"`
+}
+
+func TestHyperlink_RoundTrip(t *testing.T) {
+	in := Bookmark{
+		Name: "Go",
+		Link: Hyperlink{URL: "https://go.dev", Text: "Go"},
+		Docs: &Hyperlink{URL: "https://pkg.go.dev"},
+	}
+
+	values, err := structToValues(in)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{"Go", `=HYPERLINK("https://go.dev","Go")`, `=HYPERLINK("https://pkg.go.dev")`}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("structToValues() = %v, want %v", values, want)
+	}
+
+	var out Bookmark
+	if err := scanRow(values, []interface{}{"Name", "Link", "Docs"}, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	in.Docs.Text = in.Docs.URL
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("scanRow() = %+v (docs %+v), want %+v", out, out.Docs, in)
+	}
+
+	text, err := structToValues(Bookmark{Link: Hyperlink{Text: "no link"}})
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	if text[1] != "no link" || text[2] != "" {
+		t.Errorf("structToValues() = %v, want plain text and an empty cell", text)
+	}
+}
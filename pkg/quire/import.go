@@ -0,0 +1,148 @@
+package quire
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ImportOptions configures ImportAllWithOptions.
+type ImportOptions struct {
+	// Append adds each file's data rows below the sheet's existing rows
+	// instead of replacing its contents. The file's header row must match
+	// the sheet's; a sheet that is empty or missing gets the whole file.
+	Append bool
+}
+
+// ImportAll restores a backup made by ExportAll: every "<name>.csv" file
+// in dir replaces the contents of the sheet called name, which is created
+// if it doesn't exist.
+func (db *DB) ImportAll(ctx context.Context, dir string) error {
+	return db.ImportAllWithOptions(ctx, dir, ImportOptions{})
+}
+
+// ImportAllWithOptions is ImportAll with options, e.g. to append to the
+// existing sheets instead of replacing them.
+//
+// Cells that read back exactly as a number or as true/false are imported
+// as numbers and booleans; everything else, such as "007", stays text.
+func (db *DB) ImportAllWithOptions(ctx context.Context, dir string, opts ImportOptions) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read import directory: %w", err)
+	}
+
+	names, err := db.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !strings.EqualFold(ext, ".csv") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ext)
+
+		rows, err := readCSVFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		if !existing[name] {
+			if err := db.client.AddSheet(ctx, name); err != nil {
+				return err
+			}
+		}
+
+		if err := db.Table(name).importRows(ctx, rows, existing[name], opts); err != nil {
+			return fmt.Errorf("failed to import sheet %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// importRows writes rows, header first, into the table according to opts.
+// existed reports whether the sheet was there before the import.
+func (t *Table) importRows(ctx context.Context, rows [][]interface{}, existed bool, opts ImportOptions) error {
+	if existed && !opts.Append {
+		if err := t.db.client.Clear(ctx, t.name); err != nil {
+			return err
+		}
+	}
+
+	if existed && opts.Append && len(rows) > 0 {
+		headers, err := t.readHeaders(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read headers: %w", err)
+		}
+		if len(headers) > 0 {
+			if !sameHeaders(headers, rows[0]) {
+				return fmt.Errorf("file header %v does not match sheet header %v", rows[0], headers)
+			}
+			rows = rows[1:]
+		}
+	}
+
+	return t.appendValues(ctx, rows)
+}
+
+// sameHeaders reports whether two header rows name the same columns in
+// the same order.
+func sameHeaders(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if fmt.Sprintf("%v", a[i]) != fmt.Sprintf("%v", b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func readCSVFile(path string) ([][]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]interface{}, len(records))
+	for i, record := range records {
+		rows[i] = make([]interface{}, len(record))
+		for j, field := range record {
+			rows[i][j] = parseCSVCell(field)
+		}
+	}
+	return rows, nil
+}
+
+// parseCSVCell converts a CSV field to a number or boolean if it formats
+// back to exactly the same text, so the import doesn't lose leading zeros
+// or other formatting that a sheet would keep as text.
+func parseCSVCell(s string) interface{} {
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) && formatCell(f) == s {
+		return f
+	}
+	if b, err := strconv.ParseBool(s); err == nil && strconv.FormatBool(b) == s {
+		return b
+	}
+	return s
+}
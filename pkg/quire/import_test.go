@@ -0,0 +1,143 @@
+package quire
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeCSVFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestDB_ImportAll(t *testing.T) {
+	dir := writeCSVFiles(t, map[string]string{
+		"Users.csv":  "ID,Name,Zip\n1,Alice,007\n2,Bob,90210\n",
+		"Orders.csv": "ID,Paid\n10,true\n",
+		"notes.txt":  "ignored",
+	})
+
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{{Title: "Users"}}, nil
+		},
+		ClearFunc: func(ctx context.Context, range_ string) error {
+			return nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	if err := (&DB{client: mock}).ImportAll(context.Background(), dir); err != nil {
+		t.Fatalf("ImportAll() unexpected error = %v", err)
+	}
+
+	if !reflect.DeepEqual(mock.AddSheetCalls, []string{"Orders"}) {
+		t.Errorf("ImportAll() added sheets %v, want [Orders]", mock.AddSheetCalls)
+	}
+	if len(mock.ClearCalls) != 1 || mock.ClearCalls[0].Range_ != "Users" {
+		t.Errorf("ImportAll() clear calls = %v, want only the existing Users sheet", mock.ClearCalls)
+	}
+
+	got := make(map[string][][]interface{})
+	for _, call := range mock.AppendCalls {
+		got[call.Range_] = append(got[call.Range_], call.Values...)
+	}
+	want := map[string][][]interface{}{
+		"Users!A1": {
+			{"ID", "Name", "Zip"},
+			{1.0, "Alice", "007"},
+			{2.0, "Bob", 90210.0},
+		},
+		"Orders!A1": {
+			{"ID", "Paid"},
+			{10.0, true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportAll() appended %v, want %v", got, want)
+	}
+}
+
+func TestDB_ImportAll_Append(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+		want    [][]interface{}
+	}{
+		{
+			name: "matching header",
+			file: "ID,Name\n3,Carol\n",
+			want: [][]interface{}{{3.0, "Carol"}},
+		},
+		{
+			name:    "different header",
+			file:    "ID,Email\n3,carol@test.com\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeCSVFiles(t, map[string]string{"Users.csv": tt.file})
+			mock := &MockSheetsClient{
+				ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+					return []SheetProperties{{Title: "Users"}}, nil
+				},
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return [][]interface{}{{"ID", "Name"}}, nil
+				},
+				AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return nil
+				},
+			}
+
+			err := (&DB{client: mock}).ImportAllWithOptions(context.Background(), dir, ImportOptions{Append: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImportAllWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(mock.ClearCalls) != 0 {
+				t.Errorf("append mode cleared the sheet: %v", mock.ClearCalls)
+			}
+			if tt.wantErr {
+				if len(mock.AppendCalls) != 0 {
+					t.Errorf("appended %v despite header mismatch", mock.AppendCalls)
+				}
+				return
+			}
+			if len(mock.AppendCalls) != 1 || !reflect.DeepEqual(mock.AppendCalls[0].Values, tt.want) {
+				t.Errorf("append calls = %v, want %v", mock.AppendCalls, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVCell(t *testing.T) {
+	tests := map[string]interface{}{
+		"42":    42.0,
+		"-1.5":  -1.5,
+		"007":   "007",
+		"1e3":   "1e3",
+		"true":  true,
+		"TRUE":  "TRUE",
+		"NaN":   "NaN",
+		"":      "",
+		"hello": "hello",
+	}
+	for in, want := range tests {
+		if got := parseCSVCell(in); got != want {
+			t.Errorf("parseCSVCell(%q) = %#v, want %#v", in, got, want)
+		}
+	}
+}
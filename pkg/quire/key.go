@@ -0,0 +1,89 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// WithKey returns a handle for the same sheet whose primary-key column,
+// used by Find, UpdateByKey and DeleteByKey, is column. It keeps the
+// handle's other options.
+func (t *Table) WithKey(column string) *Table {
+	opts := t.opts
+	opts.Key = column
+	return t.withOptions(opts)
+}
+
+// Find scans the row whose primary key equals key into dest, which must be
+// a pointer to a struct. It returns ErrNoRows if there is no such row.
+func (t *Table) Find(ctx context.Context, key interface{}, dest interface{}) error {
+	column, err := t.keyColumn(dest)
+	if err != nil {
+		return err
+	}
+	return t.Query().Where(column, "=", key).First(ctx, dest)
+}
+
+// UpdateByKey writes record over the row whose primary key equals key, as
+// Update does for a row index. It returns ErrNoRows if there is no such
+// row; if several rows share the key, all of them are updated.
+func (t *Table) UpdateByKey(ctx context.Context, key interface{}, record interface{}) error {
+	column, err := t.keyColumn(record)
+	if err != nil {
+		return err
+	}
+
+	headers, indices, err := t.matchingRows(ctx, column, "=", key)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return fmt.Errorf("%w: no row with %s %v", ErrNoRows, column, key)
+	}
+	return t.updateRows(ctx, headers, indices, record)
+}
+
+// DeleteByKey removes the row whose primary key equals key. As there is no
+// record to take a pk field from, the key column must be set with WithKey
+// or TableOptions.Key. It returns ErrNoRows if there is no such row; if
+// several rows share the key, all of them are deleted.
+func (t *Table) DeleteByKey(ctx context.Context, key interface{}) error {
+	column, err := t.keyColumn(nil)
+	if err != nil {
+		return err
+	}
+
+	_, indices, err := t.matchingRows(ctx, column, "=", key)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return fmt.Errorf("%w: no row with %s %v", ErrNoRows, column, key)
+	}
+	return t.deleteRows(ctx, indices)
+}
+
+// keyColumn returns the table's primary-key column: TableOptions.Key, or
+// else the column of record's field tagged "pk". record may be a struct or
+// a pointer to one, or nil.
+func (t *Table) keyColumn(record interface{}) (string, error) {
+	if t.opts.Key != "" {
+		return t.opts.Key, nil
+	}
+
+	if record != nil {
+		typ := reflect.TypeOf(record)
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() == reflect.Struct {
+			for _, sf := range t.naming().structFields(typ) {
+				if sf.tag.pk {
+					return sf.tag.name, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("table %s has no key column: use WithKey or tag a field with pk", t.name)
+}
@@ -0,0 +1,145 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type KeyedUser struct {
+	ID    int    `quire:"ID,pk"`
+	Name  string `quire:"Name"`
+	Email string `quire:"Email"`
+}
+
+func newKeyedMock() *MockSheetsClient {
+	return &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email"},
+				{1.0, "Alice", "alice@test.com"},
+				{2.0, "Bob", "bob@test.com"},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+		DeleteRowsFunc: func(ctx context.Context, sheetName string, rowIndices []int) error {
+			return nil
+		},
+	}
+}
+
+func TestTable_Find(t *testing.T) {
+	ctx := context.Background()
+	table := (&DB{client: newKeyedMock()}).Table("Users")
+
+	var u KeyedUser
+	if err := table.Find(ctx, 2, &u); err != nil {
+		t.Fatalf("Find() unexpected error = %v", err)
+	}
+	if want := (KeyedUser{ID: 2, Name: "Bob", Email: "bob@test.com"}); u != want {
+		t.Errorf("Find() = %+v, want %+v", u, want)
+	}
+
+	if err := table.Find(ctx, 3, &u); !errors.Is(err, ErrNoRows) {
+		t.Errorf("Find() error = %v, want ErrNoRows", err)
+	}
+
+	var byEmail TestUser
+	if err := table.WithKey("Email").Find(ctx, "alice@test.com", &byEmail); err != nil {
+		t.Fatalf("WithKey().Find() unexpected error = %v", err)
+	}
+	if byEmail.ID != 1 {
+		t.Errorf("WithKey().Find() = %+v, want Alice", byEmail)
+	}
+
+	if err := table.Find(ctx, 1, &byEmail); err == nil {
+		t.Error("Find() expected error without a key column")
+	}
+}
+
+func TestTable_UpdateByKey(t *testing.T) {
+	ctx := context.Background()
+	mock := newKeyedMock()
+	table := (&DB{client: mock}).Table("Users")
+
+	if err := table.UpdateByKey(ctx, 2, KeyedUser{ID: 2, Name: "Robert", Email: "rob@test.com"}); err != nil {
+		t.Fatalf("UpdateByKey() unexpected error = %v", err)
+	}
+	want := map[string][][]interface{}{"Users!A3:C3": {{2, "Robert", "rob@test.com"}}}
+	if len(mock.BatchWriteCalls) != 1 || !reflect.DeepEqual(mock.BatchWriteCalls[0].Data, want) {
+		t.Errorf("UpdateByKey() wrote %+v, want %v", mock.BatchWriteCalls, want)
+	}
+
+	err := table.UpdateByKey(ctx, 9, KeyedUser{ID: 9})
+	if !errors.Is(err, ErrNoRows) {
+		t.Errorf("UpdateByKey() error = %v, want ErrNoRows", err)
+	}
+	if len(mock.BatchWriteCalls) != 1 {
+		t.Errorf("UpdateByKey() of a missing key wrote %d times, want no write", len(mock.BatchWriteCalls)-1)
+	}
+}
+
+func TestTable_FindAndUpdateByKey_LargeNumbers(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email"},
+				{1e6, "Alice", "alice@test.com"},
+				{"2500000", "Bob", "bob@test.com"},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	var u KeyedUser
+	if err := table.Find(ctx, 1000000, &u); err != nil {
+		t.Fatalf("Find() unexpected error = %v", err)
+	}
+	if u.Name != "Alice" {
+		t.Errorf("Find() = %+v, want Alice", u)
+	}
+
+	type floatKeyed struct {
+		ID   float64 `quire:"ID,pk"`
+		Name string  `quire:"Name"`
+	}
+	if err := table.UpdateByKey(ctx, 2.5e6, floatKeyed{ID: 2.5e6, Name: "Robert"}); err != nil {
+		t.Fatalf("UpdateByKey() unexpected error = %v", err)
+	}
+	want := map[string][][]interface{}{"Users!A3:B3": {{2.5e6, "Robert"}}}
+	if len(mock.BatchWriteCalls) != 1 || !reflect.DeepEqual(mock.BatchWriteCalls[0].Data, want) {
+		t.Errorf("UpdateByKey() wrote %+v, want %v", mock.BatchWriteCalls, want)
+	}
+}
+
+func TestTable_DeleteByKey(t *testing.T) {
+	ctx := context.Background()
+	mock := newKeyedMock()
+	db := &DB{client: mock}
+
+	if err := db.Table("Users").DeleteByKey(ctx, 1); err == nil {
+		t.Error("DeleteByKey() expected error without a key column")
+	}
+
+	table := db.Table("Users").WithKey("ID")
+	if err := table.DeleteByKey(ctx, 1); err != nil {
+		t.Fatalf("DeleteByKey() unexpected error = %v", err)
+	}
+	if len(mock.DeleteRowsCalls) != 1 || !reflect.DeepEqual(mock.DeleteRowsCalls[0].RowIndices, []int{1}) {
+		t.Errorf("DeleteByKey() calls = %+v, want row index 1", mock.DeleteRowsCalls)
+	}
+	if len(mock.ReadCalls) != 1 {
+		t.Errorf("DeleteByKey() read the sheet %d times, want once", len(mock.ReadCalls))
+	}
+
+	if err := table.DeleteByKey(ctx, 5); !errors.Is(err, ErrNoRows) {
+		t.Errorf("DeleteByKey() error = %v, want ErrNoRows", err)
+	}
+}
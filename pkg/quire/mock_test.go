@@ -6,17 +6,43 @@ import (
 )
 
 type MockSheetsClient struct {
-	ReadFunc       func(ctx context.Context, range_ string) ([][]interface{}, error)
-	WriteFunc      func(ctx context.Context, range_ string, values [][]interface{}) error
-	AppendFunc     func(ctx context.Context, range_ string, values [][]interface{}) error
-	ClearFunc      func(ctx context.Context, range_ string) error
-	DeleteRowsFunc func(ctx context.Context, sheetName string, rowIndices []int) error
+	ReadFunc        func(ctx context.Context, range_ string) ([][]interface{}, error)
+	BatchReadFunc   func(ctx context.Context, ranges []string) (map[string][][]interface{}, error)
+	WriteFunc       func(ctx context.Context, range_ string, values [][]interface{}) error
+	BatchWriteFunc  func(ctx context.Context, data map[string][][]interface{}) error
+	AppendFunc      func(ctx context.Context, range_ string, values [][]interface{}) error
+	ClearFunc       func(ctx context.Context, range_ string) error
+	DeleteRowsFunc  func(ctx context.Context, sheetName string, rowIndices []int) error
+	ListSheetsFunc  func(ctx context.Context) ([]SheetProperties, error)
+	AddSheetFunc    func(ctx context.Context, sheetName string) error
+	DeleteSheetFunc func(ctx context.Context, sheetName string) error
+	ExpandSheetFunc func(ctx context.Context, sheetName string, rows, columns int) error
 
-	ReadCalls       []MockCall
-	WriteCalls      []MockCall
-	AppendCalls     []MockCall
-	ClearCalls      []MockCall
-	DeleteRowsCalls []DeleteRowsCall
+	ReadCalls        []MockCall
+	BatchReadCalls   []BatchReadCall
+	WriteCalls       []MockCall
+	BatchWriteCalls  []BatchWriteCall
+	AppendCalls      []MockCall
+	ClearCalls       []MockCall
+	DeleteRowsCalls  []DeleteRowsCall
+	ListSheetsCalls  int
+	AddSheetCalls    []string
+	DeleteSheetCalls []string
+	ExpandSheetCalls []ExpandSheetCall
+}
+
+type ExpandSheetCall struct {
+	SheetName string
+	Rows      int
+	Columns   int
+}
+
+type BatchReadCall struct {
+	Ranges []string
+}
+
+type BatchWriteCall struct {
+	Data map[string][][]interface{}
 }
 
 type DeleteRowsCall struct {
@@ -37,6 +63,14 @@ func (m *MockSheetsClient) Read(ctx context.Context, range_ string) ([][]interfa
 	return nil, fmt.Errorf("Read not implemented")
 }
 
+func (m *MockSheetsClient) BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+	m.BatchReadCalls = append(m.BatchReadCalls, BatchReadCall{Ranges: ranges})
+	if m.BatchReadFunc != nil {
+		return m.BatchReadFunc(ctx, ranges)
+	}
+	return nil, fmt.Errorf("BatchRead not implemented")
+}
+
 func (m *MockSheetsClient) Write(ctx context.Context, range_ string, values [][]interface{}) error {
 	m.WriteCalls = append(m.WriteCalls, MockCall{Range_: range_, Values: values})
 	if m.WriteFunc != nil {
@@ -45,6 +79,14 @@ func (m *MockSheetsClient) Write(ctx context.Context, range_ string, values [][]
 	return fmt.Errorf("Write not implemented")
 }
 
+func (m *MockSheetsClient) BatchWrite(ctx context.Context, data map[string][][]interface{}) error {
+	m.BatchWriteCalls = append(m.BatchWriteCalls, BatchWriteCall{Data: data})
+	if m.BatchWriteFunc != nil {
+		return m.BatchWriteFunc(ctx, data)
+	}
+	return fmt.Errorf("BatchWrite not implemented")
+}
+
 func (m *MockSheetsClient) Append(ctx context.Context, range_ string, values [][]interface{}) error {
 	m.AppendCalls = append(m.AppendCalls, MockCall{Range_: range_, Values: values})
 	if m.AppendFunc != nil {
@@ -69,10 +111,48 @@ func (m *MockSheetsClient) DeleteRows(ctx context.Context, sheetName string, row
 	return nil
 }
 
+func (m *MockSheetsClient) ListSheets(ctx context.Context) ([]SheetProperties, error) {
+	m.ListSheetsCalls++
+	if m.ListSheetsFunc != nil {
+		return m.ListSheetsFunc(ctx)
+	}
+	return nil, fmt.Errorf("ListSheets not implemented")
+}
+
+func (m *MockSheetsClient) AddSheet(ctx context.Context, sheetName string) error {
+	m.AddSheetCalls = append(m.AddSheetCalls, sheetName)
+	if m.AddSheetFunc != nil {
+		return m.AddSheetFunc(ctx, sheetName)
+	}
+	return nil
+}
+
+func (m *MockSheetsClient) DeleteSheet(ctx context.Context, sheetName string) error {
+	m.DeleteSheetCalls = append(m.DeleteSheetCalls, sheetName)
+	if m.DeleteSheetFunc != nil {
+		return m.DeleteSheetFunc(ctx, sheetName)
+	}
+	return nil
+}
+
+func (m *MockSheetsClient) ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error {
+	m.ExpandSheetCalls = append(m.ExpandSheetCalls, ExpandSheetCall{SheetName: sheetName, Rows: rows, Columns: columns})
+	if m.ExpandSheetFunc != nil {
+		return m.ExpandSheetFunc(ctx, sheetName, rows, columns)
+	}
+	return nil
+}
+
 func (m *MockSheetsClient) Reset() {
 	m.ReadCalls = nil
+	m.BatchReadCalls = nil
 	m.WriteCalls = nil
+	m.BatchWriteCalls = nil
 	m.AppendCalls = nil
 	m.ClearCalls = nil
 	m.DeleteRowsCalls = nil
+	m.ListSheetsCalls = 0
+	m.AddSheetCalls = nil
+	m.DeleteSheetCalls = nil
+	m.ExpandSheetCalls = nil
 }
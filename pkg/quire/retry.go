@@ -0,0 +1,203 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// defaultRetryBaseDelay is used when Config.RetryBaseDelay is not set.
+	defaultRetryBaseDelay = 500 * time.Millisecond
+
+	// maxRetryDelay caps the exponential backoff between attempts.
+	maxRetryDelay = 30 * time.Second
+)
+
+// retryPolicy describes how often and how fast failed calls are retried.
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+}
+
+// backoff returns the delay before the given retry (1 for the first retry):
+// exponential in the attempt number, capped, with jitter in [d/2, d).
+func (p retryPolicy) backoff(retry int) time.Duration {
+	d := p.baseDelay << (retry - 1)
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// delay returns how long to wait before the given retry after err. A
+// server-provided Retry-After takes precedence over the computed backoff.
+func (p retryPolicy) delay(retry int, err error) time.Duration {
+	var quotaErr *QuotaExceededError
+	if errors.As(err, &quotaErr) && quotaErr.RetryAfter > 0 {
+		return quotaErr.RetryAfter
+	}
+	return p.backoff(retry)
+}
+
+// retryClient wraps a SheetsClient and retries calls that fail with a
+// transient HTTP status.
+//
+// Only idempotent calls (Read, BatchRead, ListSheets, and Write, BatchWrite
+// and Clear of fixed ranges) are retried on any transient status (429 or 5xx): repeating
+// them after a failure that was in fact applied leaves the sheet the same.
+// The rest change the sheet relative to its current state, so a retry after
+// a 5xx or a timeout, where the first attempt may have gone through, could
+// append rows twice, delete the wrong rows, or grow the sheet twice. Those
+// (Append, DeleteRows, AddSheet, DeleteSheet, ExpandSheet) are retried only
+// on 429, which the API returns before processing the request.
+type retryClient struct {
+	next   SheetsClient
+	policy retryPolicy
+}
+
+func newRetryClient(next SheetsClient, policy retryPolicy) *retryClient {
+	if policy.baseDelay <= 0 {
+		policy.baseDelay = defaultRetryBaseDelay
+	}
+	return &retryClient{next: next, policy: policy}
+}
+
+// do runs op, retrying it while it fails with an error for which
+// retryable reports true.
+func (c *retryClient) do(ctx context.Context, retryable func(error) bool, op func() error) error {
+	var err error
+	for attempt := 0; attempt < c.policy.attempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := sleepContext(ctx, c.policy.delay(attempt, err)); waitErr != nil {
+				return err
+			}
+		}
+
+		err = op()
+		if err == nil || !retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryable reports whether err is a Sheets API error worth retrying.
+func isRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isRejected reports whether err shows the request was refused without
+// being applied, so that even a non-idempotent call is safe to repeat.
+func isRejected(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests
+}
+
+func (c *retryClient) Read(ctx context.Context, range_ string) ([][]interface{}, error) {
+	var values [][]interface{}
+	err := c.do(ctx, isRetryable, func() error {
+		var err error
+		values, err = c.next.Read(ctx, range_)
+		return err
+	})
+	return values, err
+}
+
+func (c *retryClient) BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+	var values map[string][][]interface{}
+	err := c.do(ctx, isRetryable, func() error {
+		var err error
+		values, err = c.next.BatchRead(ctx, ranges)
+		return err
+	})
+	return values, err
+}
+
+func (c *retryClient) Write(ctx context.Context, range_ string, values [][]interface{}) error {
+	return c.do(ctx, isRetryable, func() error {
+		return c.next.Write(ctx, range_, values)
+	})
+}
+
+func (c *retryClient) BatchWrite(ctx context.Context, data map[string][][]interface{}) error {
+	return c.do(ctx, isRetryable, func() error {
+		return c.next.BatchWrite(ctx, data)
+	})
+}
+
+func (c *retryClient) Append(ctx context.Context, range_ string, values [][]interface{}) error {
+	return c.do(ctx, isRejected, func() error {
+		return c.next.Append(ctx, range_, values)
+	})
+}
+
+func (c *retryClient) Clear(ctx context.Context, range_ string) error {
+	return c.do(ctx, isRetryable, func() error {
+		return c.next.Clear(ctx, range_)
+	})
+}
+
+func (c *retryClient) DeleteRows(ctx context.Context, sheetName string, rowIndices []int) error {
+	return c.do(ctx, isRejected, func() error {
+		return c.next.DeleteRows(ctx, sheetName, rowIndices)
+	})
+}
+
+func (c *retryClient) ListSheets(ctx context.Context) ([]SheetProperties, error) {
+	var sheets []SheetProperties
+	err := c.do(ctx, isRetryable, func() error {
+		var err error
+		sheets, err = c.next.ListSheets(ctx)
+		return err
+	})
+	return sheets, err
+}
+
+func (c *retryClient) AddSheet(ctx context.Context, sheetName string) error {
+	return c.do(ctx, isRejected, func() error {
+		return c.next.AddSheet(ctx, sheetName)
+	})
+}
+
+func (c *retryClient) DeleteSheet(ctx context.Context, sheetName string) error {
+	return c.do(ctx, isRejected, func() error {
+		return c.next.DeleteSheet(ctx, sheetName)
+	})
+}
+
+func (c *retryClient) ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error {
+	return c.do(ctx, isRejected, func() error {
+		return c.next.ExpandSheet(ctx, sheetName, rows, columns)
+	})
+}
@@ -0,0 +1,182 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryClient_RetriesTransientErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		failures  int
+		code      int
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"succeeds after two 503s", 2, http.StatusServiceUnavailable, 4, 3, false},
+		{"succeeds after a 429", 1, http.StatusTooManyRequests, 3, 2, false},
+		{"gives up after max attempts", 5, http.StatusServiceUnavailable, 3, 3, true},
+		{"non-retryable error returned immediately", 1, http.StatusNotFound, 4, 1, true},
+		{"no failures", 0, 0, 3, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := tt.failures
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					if failures > 0 {
+						failures--
+						return nil, &googleapi.Error{Code: tt.code}
+					}
+					return [][]interface{}{{"ID"}}, nil
+				},
+			}
+
+			client := newRetryClient(mock, retryPolicy{attempts: tt.attempts, baseDelay: time.Millisecond})
+			_, err := client.Read(ctx, "Users")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(mock.ReadCalls) != tt.wantCalls {
+				t.Errorf("Read() calls = %d, want %d", len(mock.ReadCalls), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryClient_WrappedErrorIsRetryable(t *testing.T) {
+	calls := 0
+	mock := &MockSheetsClient{
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			calls++
+			if calls == 1 {
+				return errors.Join(errors.New("failed to write"), &googleapi.Error{Code: http.StatusBadGateway})
+			}
+			return nil
+		},
+	}
+
+	client := newRetryClient(mock, retryPolicy{attempts: 3, baseDelay: time.Millisecond})
+	if err := client.Write(context.Background(), "Users!A2", nil); err != nil {
+		t.Errorf("Write() unexpected error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Write() calls = %d, want 2", calls)
+	}
+}
+
+func TestRetryClient_NonIdempotentCalls(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		code       int
+		wantAppend int
+		wantRead   int
+	}{
+		{"server error may have applied", http.StatusServiceUnavailable, 1, 2},
+		{"timeout may have applied", http.StatusGatewayTimeout, 1, 2},
+		{"rate limit was not applied", http.StatusTooManyRequests, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return &googleapi.Error{Code: tt.code}
+				},
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return nil, &googleapi.Error{Code: tt.code}
+				},
+			}
+
+			client := newRetryClient(mock, retryPolicy{attempts: 2, baseDelay: time.Millisecond})
+			client.Append(ctx, "Users", [][]interface{}{{1.0}})
+			client.Read(ctx, "Users")
+
+			if len(mock.AppendCalls) != tt.wantAppend {
+				t.Errorf("Append() calls = %d, want %d", len(mock.AppendCalls), tt.wantAppend)
+			}
+			if len(mock.ReadCalls) != tt.wantRead {
+				t.Errorf("Read() calls = %d, want %d", len(mock.ReadCalls), tt.wantRead)
+			}
+		})
+	}
+}
+
+func TestRetryClient_RespectsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			cancel()
+			return nil, &googleapi.Error{Code: http.StatusServiceUnavailable}
+		},
+	}
+
+	client := newRetryClient(mock, retryPolicy{attempts: 5, baseDelay: time.Hour})
+	_, err := client.Read(ctx, "Users")
+
+	if err == nil {
+		t.Fatal("Read() expected error")
+	}
+	if len(mock.ReadCalls) != 1 {
+		t.Errorf("Read() calls = %d, want 1 after cancellation", len(mock.ReadCalls))
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := retryPolicy{baseDelay: 100 * time.Millisecond}
+
+	for retry, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond} {
+		d := p.backoff(retry)
+		if d < max/2 || d > max {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", retry, d, max/2, max)
+		}
+	}
+
+	if d := p.backoff(40); d > maxRetryDelay {
+		t.Errorf("backoff(40) = %v, want capped at %v", d, maxRetryDelay)
+	}
+}
+
+func TestRetryClient_HonorsRetryAfter(t *testing.T) {
+	calls := 0
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, &QuotaExceededError{
+					RetryAfter: time.Millisecond,
+					Err:        &googleapi.Error{Code: http.StatusTooManyRequests},
+				}
+			}
+			return [][]interface{}{{"ID"}}, nil
+		},
+	}
+
+	// The computed backoff would be an hour; Retry-After must win.
+	client := newRetryClient(mock, retryPolicy{attempts: 2, baseDelay: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Read(ctx, "Users"); err != nil {
+		t.Errorf("Read() unexpected error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Read() calls = %d, want 2", calls)
+	}
+}
+
+func TestRetryClientInterface(t *testing.T) {
+	var _ SheetsClient = (*retryClient)(nil)
+}
@@ -0,0 +1,107 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RowIterator steps through the results of a query one row at a time, in
+// the manner of sql.Rows:
+//
+//	rows, err := db.Table("Users").Query().Where("Age", ">", 18).Rows(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for rows.Next() {
+//		var u User
+//		if err := rows.Scan(&u); err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//	return rows.Err()
+//
+// The sheet is read and filtered when the iterator is created, but each row
+// is only converted to a struct when it is scanned, so no slice of records
+// is allocated up front.
+type RowIterator struct {
+	query   *Query
+	scanner scanner
+	headers []interface{}
+	rows    [][]interface{}
+	index   int
+	err     error
+}
+
+// Rows runs the query and returns an iterator over the matching rows, in
+// order and limited as for Get.
+func (q *Query) Rows(ctx context.Context) (*RowIterator, error) {
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	headers, rows, err := q.rows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RowIterator{
+		query:   q,
+		scanner: q.scanner(),
+		headers: headers,
+		rows:    q.applyLimit(rows),
+		index:   -1,
+	}, nil
+}
+
+// Next advances to the next row, reporting false when there are no more
+// rows or a previous Scan failed.
+func (it *RowIterator) Next() bool {
+	if it.err != nil || it.index+1 >= len(it.rows) {
+		it.index = len(it.rows)
+		return false
+	}
+	it.index++
+	return true
+}
+
+// Scan copies the current row into dest, which must be a pointer to a
+// struct, and applies the query's mappers. With StrictScan, a row that
+// fails to scan returns its ScanErrors, which Err then reports too.
+func (it *RowIterator) Scan(dest interface{}) error {
+	if it.index < 0 || it.index >= len(it.rows) {
+		return fmt.Errorf("Scan called without a successful call to Next")
+	}
+
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a pointer to a struct")
+	}
+
+	if err := it.scanner.scanRow(it.rows[it.index], it.headers, destVal); err != nil {
+		if scanErrs, ok := err.(ScanErrors); ok {
+			for _, e := range scanErrs {
+				e.Row = it.index
+			}
+		}
+		it.err = err
+		return err
+	}
+	if err := it.query.applyMappers(destVal.Elem()); err != nil {
+		it.err = err
+		return err
+	}
+	return nil
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Close releases the iterator's rows. Further calls to Next return false.
+func (it *RowIterator) Close() error {
+	it.rows = nil
+	it.index = 0
+	return nil
+}
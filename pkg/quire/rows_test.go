@@ -0,0 +1,123 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Rows(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+				{2.0, "Bob", "bob@test.com", 17.0},
+				{3.0, "Carol", "carol@test.com", 45.0},
+				{4.0, "Dave", "dave@test.com", 52.0},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	rows, err := table.Query().Where("Age", ">", 18).OrderBy("Age", true).Limit(2).Rows(ctx)
+	if err != nil {
+		t.Fatalf("Rows() unexpected error = %v", err)
+	}
+
+	var got []TestUser
+	for rows.Next() {
+		var u TestUser
+		if err := rows.Scan(&u); err != nil {
+			t.Fatalf("Scan() unexpected error = %v", err)
+		}
+		got = append(got, u)
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+	want := []TestUser{
+		{ID: 4, Name: "Dave", Email: "dave@test.com", Age: 52},
+		{ID: 3, Name: "Carol", Email: "carol@test.com", Age: 45},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows() scanned %+v, want %+v", got, want)
+	}
+
+	if rows.Next() {
+		t.Error("Next() after the last row = true, want false")
+	}
+	var u TestUser
+	if err := rows.Scan(&u); err == nil {
+		t.Error("Scan() after the last row expected an error")
+	}
+}
+
+func TestQuery_Rows_Empty(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return nil, nil
+		},
+	}
+
+	rows, err := (&DB{client: mock}).Table("Users").Query().Rows(context.Background())
+	if err != nil {
+		t.Fatalf("Rows() unexpected error = %v", err)
+	}
+	if rows.Next() {
+		t.Error("Next() on an empty sheet = true, want false")
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestQuery_Rows_Errors(t *testing.T) {
+	ctx := context.Background()
+	readErr := errors.New("read failed")
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return nil, readErr
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+	if _, err := table.Query().Rows(ctx); !errors.Is(err, readErr) {
+		t.Errorf("Rows() error = %v, want %v", err, readErr)
+	}
+
+	mock.ReadFunc = func(ctx context.Context, range_ string) ([][]interface{}, error) {
+		return [][]interface{}{
+			{"ID", "Name"},
+			{"x", "Alice"},
+			{2.0, "Bob"},
+		}, nil
+	}
+	rows, err := table.Query().StrictScan().Rows(ctx)
+	if err != nil {
+		t.Fatalf("Rows() unexpected error = %v", err)
+	}
+
+	var u TestUser
+	if err := rows.Scan(&u); err == nil {
+		t.Error("Scan() before Next() expected an error")
+	}
+	if !rows.Next() {
+		t.Fatal("Next() = false, want the first row")
+	}
+	if err := rows.Scan(u); err == nil {
+		t.Error("Scan() expected error for non-pointer destination")
+	}
+
+	var scanErrs ScanErrors
+	if err := rows.Scan(&u); !errors.As(err, &scanErrs) {
+		t.Fatalf("Scan() error = %v, want ScanErrors", err)
+	}
+	if rows.Next() {
+		t.Error("Next() after a failed Scan() = true, want false")
+	}
+	if !errors.As(rows.Err(), &scanErrs) {
+		t.Errorf("Err() = %v, want ScanErrors", rows.Err())
+	}
+}
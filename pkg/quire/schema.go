@@ -0,0 +1,95 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateTable adds a sheet called name to the spreadsheet and, if headers
+// is not empty, writes them as its header row. It fails if a sheet of that
+// name already exists; use EnsureTable to create a sheet only when it is
+// missing.
+func (db *DB) CreateTable(ctx context.Context, name string, headers []string) error {
+	if err := db.client.AddSheet(ctx, name); err != nil {
+		return err
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	row := make([]interface{}, len(headers))
+	for i, h := range headers {
+		row[i] = h
+	}
+	if err := db.client.Write(ctx, name+"!A1", [][]interface{}{row}); err != nil {
+		return fmt.Errorf("failed to write headers: %w", err)
+	}
+	return nil
+}
+
+// DropTable deletes the sheet called name, and all its data, from the
+// spreadsheet. It returns an error wrapping ErrSheetNotFound if there is no
+// such sheet.
+func (db *DB) DropTable(ctx context.Context, name string) error {
+	return db.client.DeleteSheet(ctx, name)
+}
+
+// SheetID returns the numeric ID (the "gid" in the sheet's URL) of the
+// sheet called name, as raw BatchUpdate requests need it. It returns an
+// error wrapping ErrSheetNotFound if there is no such sheet.
+func (db *DB) SheetID(ctx context.Context, name string) (int64, error) {
+	sheetList, err := db.client.ListSheets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sheets: %w", err)
+	}
+	for _, sheet := range sheetList {
+		if sheet.Title == name {
+			return sheet.SheetID, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrSheetNotFound, name)
+}
+
+// Sheets returns the numeric ID of every sheet in the spreadsheet, keyed
+// by sheet name.
+func (db *DB) Sheets(ctx context.Context) (map[string]int64, error) {
+	sheetList, err := db.client.ListSheets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sheets: %w", err)
+	}
+	ids := make(map[string]int64, len(sheetList))
+	for _, sheet := range sheetList {
+		ids[sheet.Title] = sheet.SheetID
+	}
+	return ids, nil
+}
+
+// TableExists reports whether the spreadsheet has a sheet called name.
+// Sheet names are matched exactly.
+func (db *DB) TableExists(ctx context.Context, name string) (bool, error) {
+	names, err := db.ListTables(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range names {
+		if n == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EnsureTable creates the sheet called name with the given header row, as
+// CreateTable does, unless it already exists. An existing sheet is left
+// untouched, even if its headers differ. It reports whether the sheet was
+// created.
+func (db *DB) EnsureTable(ctx context.Context, name string, headers []string) (bool, error) {
+	exists, err := db.TableExists(ctx, name)
+	if err != nil || exists {
+		return false, err
+	}
+	if err := db.CreateTable(ctx, name, headers); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,179 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// sheetListMock returns a mock whose AddSheet adds to the sheets that
+// ListSheets reports.
+func sheetListMock(titles ...string) *MockSheetsClient {
+	mock := &MockSheetsClient{
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	mock.ListSheetsFunc = func(ctx context.Context) ([]SheetProperties, error) {
+		var sheets []SheetProperties
+		for _, title := range titles {
+			sheets = append(sheets, SheetProperties{Title: title})
+		}
+		for _, title := range mock.AddSheetCalls {
+			sheets = append(sheets, SheetProperties{Title: title})
+		}
+		return sheets, nil
+	}
+	mock.AddSheetFunc = func(ctx context.Context, sheetName string) error {
+		return nil
+	}
+	return mock
+}
+
+func TestDB_CreateTable(t *testing.T) {
+	ctx := context.Background()
+	mock := sheetListMock()
+	db := &DB{client: mock}
+
+	if err := db.CreateTable(ctx, "Users", []string{"ID", "Name"}); err != nil {
+		t.Fatalf("CreateTable() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(mock.AddSheetCalls, []string{"Users"}) {
+		t.Errorf("CreateTable() added sheets %v, want [Users]", mock.AddSheetCalls)
+	}
+	want := []MockCall{{Range_: "Users!A1", Values: [][]interface{}{{"ID", "Name"}}}}
+	if !reflect.DeepEqual(mock.WriteCalls, want) {
+		t.Errorf("CreateTable() writes = %v, want %v", mock.WriteCalls, want)
+	}
+
+	mock.Reset()
+	if err := db.CreateTable(ctx, "Scratch", nil); err != nil {
+		t.Fatalf("CreateTable() unexpected error = %v", err)
+	}
+	if len(mock.WriteCalls) != 0 {
+		t.Errorf("CreateTable() without headers wrote %v", mock.WriteCalls)
+	}
+
+	mock.AddSheetFunc = func(ctx context.Context, sheetName string) error {
+		return errors.New("duplicate sheet")
+	}
+	if err := db.CreateTable(ctx, "Users", []string{"ID"}); err == nil {
+		t.Error("CreateTable() expected error when the sheet can't be added")
+	}
+}
+
+func TestDB_TableExists(t *testing.T) {
+	ctx := context.Background()
+	db := &DB{client: sheetListMock("Users", "Orders")}
+
+	tests := map[string]bool{
+		"Users":  true,
+		"Orders": true,
+		"users":  false,
+		"Logs":   false,
+	}
+	for name, want := range tests {
+		got, err := db.TableExists(ctx, name)
+		if err != nil {
+			t.Fatalf("TableExists(%q) unexpected error = %v", name, err)
+		}
+		if got != want {
+			t.Errorf("TableExists(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestDB_EnsureTable(t *testing.T) {
+	ctx := context.Background()
+	mock := sheetListMock("Users")
+	db := &DB{client: mock}
+
+	for i, want := range []bool{true, false, false} {
+		created, err := db.EnsureTable(ctx, "Events", []string{"ID", "At"})
+		if err != nil {
+			t.Fatalf("EnsureTable() call %d unexpected error = %v", i, err)
+		}
+		if created != want {
+			t.Errorf("EnsureTable() call %d created = %v, want %v", i, created, want)
+		}
+	}
+	if !reflect.DeepEqual(mock.AddSheetCalls, []string{"Events"}) {
+		t.Errorf("EnsureTable() added sheets %v, want [Events] once", mock.AddSheetCalls)
+	}
+	if len(mock.WriteCalls) != 1 {
+		t.Errorf("EnsureTable() wrote headers %d times, want 1", len(mock.WriteCalls))
+	}
+
+	if created, err := db.EnsureTable(ctx, "Users", []string{"Other"}); err != nil || created {
+		t.Errorf("EnsureTable() on an existing sheet = %v, %v; want false, nil", created, err)
+	}
+	if len(mock.WriteCalls) != 1 {
+		t.Error("EnsureTable() should leave an existing sheet's headers alone")
+	}
+}
+
+func TestDB_DropTable(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{}
+	db := &DB{client: mock}
+
+	if err := db.DropTable(ctx, "Archive"); err != nil {
+		t.Fatalf("DropTable() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(mock.DeleteSheetCalls, []string{"Archive"}) {
+		t.Errorf("DropTable() deleted sheets %v, want [Archive]", mock.DeleteSheetCalls)
+	}
+
+	mock.DeleteSheetFunc = func(ctx context.Context, sheetName string) error {
+		return fmt.Errorf("failed to get sheet ID: %w: %q", ErrSheetNotFound, sheetName)
+	}
+	if err := db.DropTable(ctx, "Missing"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("DropTable() error = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestDB_SheetIDs(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{
+				{SheetID: 0, Title: "Users"},
+				{SheetID: 184467, Title: "Orders"},
+				{SheetID: 9, Title: "Audit Log"},
+			}, nil
+		},
+	}
+	db := &DB{client: mock}
+
+	ids, err := db.Sheets(ctx)
+	if err != nil {
+		t.Fatalf("Sheets() unexpected error = %v", err)
+	}
+	want := map[string]int64{"Users": 0, "Orders": 184467, "Audit Log": 9}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Sheets() = %v, want %v", ids, want)
+	}
+
+	for name, wantID := range want {
+		id, err := db.SheetID(ctx, name)
+		if err != nil {
+			t.Fatalf("SheetID(%q) unexpected error = %v", name, err)
+		}
+		if id != wantID {
+			t.Errorf("SheetID(%q) = %d, want %d", name, id, wantID)
+		}
+	}
+
+	if _, err := db.SheetID(ctx, "Missing"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("SheetID() error = %v, want ErrSheetNotFound", err)
+	}
+
+	mock.ListSheetsFunc = func(ctx context.Context) ([]SheetProperties, error) {
+		return nil, errors.New("network error")
+	}
+	if _, err := db.Sheets(ctx); err == nil {
+		t.Error("Sheets() expected error when listing fails")
+	}
+}
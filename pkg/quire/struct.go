@@ -0,0 +1,970 @@
+package quire
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanError describes a cell that could not be converted into a struct field.
+type ScanError struct {
+	Row   int // 0-based index of the row within the scanned results
+	Field string
+	Err   error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("row %d, field %s: %v", e.Row, e.Field, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// ScanErrors collects every ScanError found while scanning a result set
+// with Query.StrictScan, so all data-quality issues are reported at once.
+type ScanErrors []*ScanError
+
+func (e ScanErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d scan errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// fieldTag is a parsed `quire:"Name,option,option:value"` struct tag.
+type fieldTag struct {
+	name string // column name; the field name if the tag has none
+	skip bool   // tagged "-"
+
+	// omitEmpty leaves the cell untouched on write when the field holds
+	// its zero value ("omitempty").
+	omitEmpty bool
+
+	// readOnly leaves the cell untouched on write but still reads it
+	// ("readonly").
+	readOnly bool
+
+	// raw receives the whole row on read and is never written ("raw").
+	raw bool
+
+	// column is the 0-based column from a "col:C" or "col:3" option, or -1
+	// to look the column up by name on read and place it by field order
+	// on write.
+	column int
+
+	// timeLayout is the time.Time layout from a "time:<layout>" option.
+	timeLayout string
+
+	// now writes a zero time field as =NOW() on insert, so the sheet
+	// records the server's time, and leaves it untouched on update
+	// ("now"). Reads also accept the date-times and serial numbers the
+	// sheet displays for it.
+	now bool
+
+	// pk marks the table's primary-key column, used by Find, UpdateByKey
+	// and DeleteByKey ("pk").
+	pk bool
+
+	// enum maps cell labels to the values of an integer field, from an
+	// "enummap=label:value|label:value" option.
+	enum []enumLabel
+}
+
+// enumLabel is one label:value pair of an enummap option.
+type enumLabel struct {
+	label string
+	value int64
+}
+
+// parseTag reads the quire tag of a struct field. Unknown options are
+// ignored.
+func parseTag(f reflect.StructField) fieldTag {
+	tag := f.Tag.Get("quire")
+	if tag == "-" {
+		return fieldTag{skip: true}
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	ft := fieldTag{name: name, column: -1}
+	if ft.name == "" {
+		ft.name = f.Name
+	}
+
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		switch {
+		case opt == "omitempty":
+			ft.omitEmpty = true
+		case opt == "readonly":
+			ft.readOnly = true
+		case opt == "raw":
+			ft.raw = true
+		case opt == "now":
+			ft.now = true
+		case opt == "pk":
+			ft.pk = true
+		case strings.HasPrefix(opt, "col:"):
+			ft.column = parseColumn(strings.TrimPrefix(opt, "col:"))
+		case strings.HasPrefix(opt, "time:"):
+			ft.timeLayout = strings.TrimPrefix(opt, "time:")
+		case strings.HasPrefix(opt, "enummap="):
+			ft.enum = parseEnumMap(strings.TrimPrefix(opt, "enummap="))
+		}
+	}
+	return ft
+}
+
+// parseEnumMap parses "active:1|inactive:0" into label:value pairs.
+// Malformed pairs are ignored.
+func parseEnumMap(s string) []enumLabel {
+	var enum []enumLabel
+	for _, pair := range strings.Split(s, "|") {
+		label, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		enum = append(enum, enumLabel{label: strings.TrimSpace(label), value: n})
+	}
+	return enum
+}
+
+// enumValue returns the value mapped to a cell label.
+func (ft fieldTag) enumValue(cell interface{}) (int64, bool) {
+	s := formatCell(cell)
+	for _, e := range ft.enum {
+		if e.label == s {
+			return e.value, true
+		}
+	}
+	return 0, false
+}
+
+// enumLabel returns the first label mapped to an integer field's value, or
+// the value itself if it has no label.
+func (ft fieldTag) enumLabel(field reflect.Value) interface{} {
+	var n int64
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = field.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = int64(field.Uint())
+	default:
+		return field.Interface()
+	}
+	for _, e := range ft.enum {
+		if e.value == n {
+			return e.label
+		}
+	}
+	return field.Interface()
+}
+
+// parseColumn parses a column given as letters ("C") or a 1-based number
+// ("3") into a 0-based index, or -1 if it is neither.
+func parseColumn(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 {
+			return -1
+		}
+		return n - 1
+	}
+	if s == "" || strings.ContainsAny(s, "0123456789") {
+		return -1
+	}
+	col, _, ok := parseCellRef(s)
+	if !ok {
+		return -1
+	}
+	return col
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// structField is a mapped field of a record struct.
+type structField struct {
+	index []int  // path from the record to the field, as for FieldByIndex
+	name  string // Go field name
+	tag   fieldTag
+}
+
+// fieldNaming derives the column name of a field whose quire tag doesn't
+// name one, from Config.DefaultTagFunc. The zero fieldNaming uses the Go
+// field name.
+type fieldNaming struct {
+	fn func(fieldName string) string
+
+	// fields maps a struct type to its []structField under fn. Function
+	// values can't be compared, so each DB keeps its own; nil disables
+	// the cache.
+	fields *sync.Map
+}
+
+func (n fieldNaming) column(fieldName string) string {
+	if n.fn == nil {
+		return fieldName
+	}
+	return n.fn(fieldName)
+}
+
+func structFields(t reflect.Type) []structField {
+	return fieldNaming{}.structFields(t)
+}
+
+// structFields lists the mapped fields of struct type t in declaration
+// order. Fields of untagged anonymous (embedded) structs, or pointers to
+// them, are flattened in where the embedded struct appears. If an outer
+// field and an embedded one map to the same column name, the outer one
+// wins. Unexported fields and fields tagged "-" are left out.
+//
+// Each type is walked once and cached, as is the result for each naming,
+// so scanning and writing many records of one type doesn't repeat the
+// reflection. The returned slice is shared and must not be modified.
+func (n fieldNaming) structFields(t reflect.Type) []structField {
+	cache := n.fields
+	if n.fn == nil {
+		cache = &plainFieldCache
+	}
+	if cache != nil {
+		if fields, ok := cache.Load(t); ok {
+			return fields.([]structField)
+		}
+	}
+
+	var candidates []fieldCandidate
+	if cached, ok := fieldCache.Load(t); ok {
+		candidates = cached.([]fieldCandidate)
+	} else {
+		candidates = walkFields(t)
+		fieldCache.Store(t, candidates)
+	}
+
+	fields := n.resolveFields(candidates)
+	if cache != nil {
+		cache.Store(t, fields)
+	}
+	return fields
+}
+
+var (
+	// fieldCache maps a struct type to its []fieldCandidate.
+	fieldCache sync.Map
+	// plainFieldCache maps a struct type to its []structField with no
+	// naming function.
+	plainFieldCache sync.Map
+)
+
+// fieldCandidate is a field found by walkFields, before the naming
+// function and the rule for duplicate names are applied.
+type fieldCandidate struct {
+	structField
+	depth int  // how many embedded structs deep the field is
+	named bool // whether the tag names the column
+}
+
+// walkFields lists every mapped field of struct type t, including those of
+// embedded structs, with their tags parsed.
+func walkFields(t reflect.Type) []fieldCandidate {
+	var all []fieldCandidate
+	var walk func(t reflect.Type, index []int, depth int)
+	walk = func(t reflect.Type, index []int, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			path := append(append([]int(nil), index...), i)
+
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && f.Tag.Get("quire") == "" && ft.Kind() == reflect.Struct && ft != timeType {
+				walk(ft, path, depth+1)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			tag := parseTag(f)
+			if tag.skip {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("quire"), ",")
+			all = append(all, fieldCandidate{structField{index: path, name: f.Name, tag: tag}, depth, name != ""})
+		}
+	}
+	walk(t, nil, 0)
+	return all
+}
+
+// resolveFields names the candidates' columns, using n for fields whose
+// tag doesn't, and keeps the shallowest field for each column name.
+func (n fieldNaming) resolveFields(candidates []fieldCandidate) []structField {
+	all := make([]fieldCandidate, len(candidates))
+	copy(all, candidates)
+	for i := range all {
+		if !all[i].named {
+			all[i].tag.name = n.column(all[i].name)
+		}
+	}
+
+	shallowest := make(map[string]int, len(all))
+	for _, c := range all {
+		if d, ok := shallowest[c.tag.name]; !ok || c.depth < d {
+			shallowest[c.tag.name] = c.depth
+		}
+	}
+
+	fields := make([]structField, 0, len(all))
+	taken := make(map[string]bool, len(all))
+	for _, c := range all {
+		if c.depth != shallowest[c.tag.name] || taken[c.tag.name] {
+			continue
+		}
+		taken[c.tag.name] = true
+		fields = append(fields, c.structField)
+	}
+	return fields
+}
+
+// fieldByIndex returns the field of struct v at index. If the path goes
+// through a nil embedded pointer, it is allocated when alloc is set and
+// settable; otherwise fieldByIndex reports false.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// scanner maps sheet rows onto structs.
+type scanner struct {
+	// strict reports cells that can't be converted to the field type
+	// instead of silently leaving the field at its zero value.
+	strict bool
+
+	// columns, if set, limits scanning to fields whose column header is
+	// in the set.
+	columns map[string]bool
+
+	// byPosition maps fields to columns by their order in the struct, as
+	// writes lay them out, instead of by header name.
+	byPosition bool
+
+	// lenient matches field names to headers ignoring surrounding
+	// whitespace and case.
+	lenient bool
+
+	// naming names the columns of untagged fields.
+	naming fieldNaming
+}
+
+// selects reports whether the column at colIdx is one of the selected
+// columns, matching names leniently if the scanner does.
+func (s scanner) selects(headers []interface{}, colIdx int) bool {
+	if colIdx >= len(headers) {
+		return false
+	}
+	if s.columns[fmt.Sprintf("%v", headers[colIdx])] {
+		return true
+	}
+	if s.lenient {
+		for name := range s.columns {
+			if findColumnLenient(headers, name, true) == colIdx {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func structSliceToValues(records interface{}) ([][]interface{}, error) {
+	return fieldNaming{}.structSliceToValues(records)
+}
+
+// structSliceToValues converts records, a slice of structs or a pointer to
+// one, into rows laid out by field order.
+func (n fieldNaming) structSliceToValues(records interface{}) ([][]interface{}, error) {
+	v := reflect.ValueOf(records)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("records must be a slice")
+	}
+
+	var result [][]interface{}
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		row, err := n.recordValues(elem.Interface(), true)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+func structToValues(record interface{}) ([]interface{}, error) {
+	return fieldNaming{}.structToValues(record)
+}
+
+func (n fieldNaming) structToValues(record interface{}) ([]interface{}, error) {
+	return n.recordValues(record, false)
+}
+
+// nowFormula is written for zero "now" fields on insert.
+const nowFormula = "=NOW()"
+
+// recordValues converts record into a row laid out by field order.
+// inserting reports whether the row is new, which only matters for "now"
+// fields.
+func (n fieldNaming) recordValues(record interface{}, inserting bool) ([]interface{}, error) {
+	v := reflect.ValueOf(record)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("record must be a struct")
+	}
+
+	var result []interface{}
+
+	next := 0
+	for _, sf := range n.structFields(v.Type()) {
+		tag := sf.tag
+		if tag.raw {
+			continue
+		}
+
+		pos := tag.column
+		if pos < 0 {
+			pos = next
+			next++
+		}
+		for len(result) <= pos {
+			result = append(result, nil)
+		}
+
+		// readonly and empty omitempty fields stay nil: the Sheets API
+		// skips null cells, so they keep their column without
+		// overwriting it. So do fields of a nil embedded pointer.
+		field, ok := fieldByIndex(v, sf.index, false)
+		if !ok || tag.readOnly || (tag.omitEmpty && field.IsZero()) {
+			continue
+		}
+		if tag.now && field.IsZero() {
+			if inserting {
+				result[pos] = nowFormula
+			}
+			continue
+		}
+		result[pos] = fieldValue(field, tag)
+	}
+
+	return result, nil
+}
+
+// structHeaders returns the header row matching the layout recordValues
+// gives record: each field's column name at its position. Columns no
+// field maps to are nil.
+func (n fieldNaming) structHeaders(record interface{}) ([]interface{}, error) {
+	t := reflect.TypeOf(record)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("record must be a struct")
+	}
+
+	var result []interface{}
+	next := 0
+	for _, sf := range n.structFields(t) {
+		if sf.tag.raw {
+			continue
+		}
+		pos := sf.tag.column
+		if pos < 0 {
+			pos = next
+			next++
+		}
+		for len(result) <= pos {
+			result = append(result, nil)
+		}
+		result[pos] = sf.tag.name
+	}
+	return result, nil
+}
+
+// structToHeaderValues converts record into a row laid out by headers:
+// each field goes to the column whose header matches its name (or to its
+// col: column). Cells with no matching field, as well as readonly and
+// empty omitempty fields, are nil so a write leaves them untouched.
+// Fields whose column isn't in headers are dropped. With lenient, names
+// match headers as Config.LenientHeaders describes.
+func (n fieldNaming) structToHeaderValues(record interface{}, headers []interface{}, lenient bool) ([]interface{}, error) {
+	v := reflect.ValueOf(record)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("record must be a struct")
+	}
+
+	result := make([]interface{}, len(headers))
+	for _, sf := range n.structFields(v.Type()) {
+		tag := sf.tag
+		field, ok := fieldByIndex(v, sf.index, false)
+		if !ok || tag.raw || tag.readOnly || (tag.omitEmpty && field.IsZero()) || (tag.now && field.IsZero()) {
+			continue
+		}
+
+		col := tag.column
+		if col < 0 {
+			col = findColumnLenient(headers, tag.name, lenient)
+		}
+		if col < 0 || col >= len(result) {
+			continue
+		}
+		result[col] = fieldValue(field, tag)
+	}
+	return result, nil
+}
+
+// fieldValue returns the cell value for a struct field. A nil pointer is
+// written as an empty cell and a non-nil one as the value it points to.
+// Times are formatted with the field's layout; the zero time is empty.
+// Types implementing encoding.TextMarshaler, such as UUIDs, are written as
+// their text. Other slices, maps and structs are written as JSON text, nil
+// ones as an empty cell.
+func fieldValue(field reflect.Value, tag fieldTag) interface{} {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return ""
+		}
+		field = field.Elem()
+	}
+
+	if field.Type() == hyperlinkType {
+		h := field.Interface().(Hyperlink)
+		if h.URL == "" {
+			return h.Text
+		}
+		return h.Formula()
+	}
+	if field.Type() == timeType {
+		t := field.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		layout := tag.timeLayout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout)
+	}
+	if tag.enum != nil {
+		return tag.enumLabel(field)
+	}
+	if m, ok := textMarshaler(field); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	switch field.Kind() {
+	case reflect.Slice, reflect.Map:
+		if field.IsNil() {
+			return ""
+		}
+		fallthrough
+	case reflect.Struct:
+		if data, err := json.Marshal(field.Interface()); err == nil {
+			return string(data)
+		}
+	}
+	return field.Interface()
+}
+
+// textMarshaler returns field as an encoding.TextMarshaler if it, or a
+// pointer to it, implements the interface.
+func textMarshaler(field reflect.Value) (encoding.TextMarshaler, bool) {
+	if m, ok := field.Interface().(encoding.TextMarshaler); ok {
+		return m, true
+	}
+	if field.CanAddr() {
+		m, ok := field.Addr().Interface().(encoding.TextMarshaler)
+		return m, ok
+	}
+	return nil, false
+}
+
+// columnValue returns the value of the record field mapped to column,
+// using the same tag rules as scanning.
+func (n fieldNaming) columnValue(record interface{}, column string) (interface{}, error) {
+	v := reflect.ValueOf(record)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("record must be a struct")
+	}
+
+	for _, sf := range n.structFields(v.Type()) {
+		if sf.tag.raw || sf.tag.name != column {
+			continue
+		}
+		if field, ok := fieldByIndex(v, sf.index, false); ok {
+			return fieldValue(field, sf.tag), nil
+		}
+		return "", nil
+	}
+
+	return nil, fmt.Errorf("record has no field for column %q", column)
+}
+
+func scanIntoSlice(rows [][]interface{}, headers []interface{}, dest interface{}) error {
+	return scanner{}.scanIntoSlice(rows, headers, dest)
+}
+
+func (s scanner) scanIntoSlice(rows [][]interface{}, headers []interface{}, dest interface{}) error {
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to a slice")
+	}
+
+	sliceVal := destVal.Elem()
+	elemType := sliceVal.Type().Elem()
+
+	var scanErrs ScanErrors
+	for i, row := range rows {
+		elem := reflect.New(elemType).Elem()
+		if err := s.scanRow(row, headers, elem); err != nil {
+			rowErrs, ok := err.(ScanErrors)
+			if !ok {
+				return err
+			}
+			for _, e := range rowErrs {
+				e.Row = i
+			}
+			scanErrs = append(scanErrs, rowErrs...)
+		}
+		sliceVal = reflect.Append(sliceVal, elem)
+	}
+
+	destVal.Elem().Set(sliceVal)
+	if len(scanErrs) > 0 {
+		return scanErrs
+	}
+	return nil
+}
+
+func scanRow(row []interface{}, headers []interface{}, dest reflect.Value) error {
+	return scanner{}.scanRow(row, headers, dest)
+}
+
+// scanRow populates dest from row. In strict mode, conversion failures
+// don't stop the scan; they are returned together as ScanErrors.
+func (s scanner) scanRow(row []interface{}, headers []interface{}, dest reflect.Value) error {
+	if dest.Kind() == reflect.Ptr {
+		dest = dest.Elem()
+	}
+	if dest.Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a struct")
+	}
+
+	var scanErrs ScanErrors
+	next := 0
+	for _, sf := range s.naming.structFields(dest.Type()) {
+		tag := sf.tag
+		if tag.raw {
+			field, ok := fieldByIndex(dest, sf.index, true)
+			if !ok {
+				continue
+			}
+			if err := assignRaw(field, row); err != nil {
+				return fmt.Errorf("failed to set field %s: %w", sf.name, err)
+			}
+			continue
+		}
+
+		colIdx := tag.column
+		if colIdx < 0 {
+			if s.byPosition {
+				colIdx = next
+				next++
+			} else {
+				colIdx = findColumnLenient(headers, tag.name, s.lenient)
+			}
+		}
+		if colIdx == -1 || colIdx >= len(row) || row[colIdx] == nil {
+			continue
+		}
+		if s.columns != nil && !s.selects(headers, colIdx) {
+			continue
+		}
+
+		value := row[colIdx]
+		if n, ok := tag.enumValue(value); ok {
+			value = n
+		}
+		layout := tag.timeLayout
+		if tag.now {
+			if t, ok := parseSheetTime(value); ok {
+				value, layout = t.Format(time.RFC3339Nano), ""
+			}
+		}
+
+		field, ok := fieldByIndex(dest, sf.index, true)
+		if !ok {
+			continue
+		}
+
+		if err := assignField(field, value, s.strict, layout); err != nil {
+			if s.strict {
+				scanErrs = append(scanErrs, &ScanError{Field: sf.name, Err: err})
+				continue
+			}
+			return fmt.Errorf("failed to set field %s: %w", sf.name, err)
+		}
+	}
+
+	if len(scanErrs) > 0 {
+		return scanErrs
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value interface{}) error {
+	return assignField(field, value, false, "")
+}
+
+// assignField converts value into field. A value that can't be parsed as
+// the field's type leaves the field unchanged; in strict mode it is also
+// reported as an error. Blank cells are never an error. layout is the
+// time.Time layout; empty means RFC 3339, falling back to a plain date.
+func assignField(field reflect.Value, value interface{}, strict bool, layout string) error {
+	if !field.CanSet() {
+		return nil
+	}
+
+	valueStr := formatCell(value)
+
+	if field.Kind() == reflect.Ptr {
+		return assignPointer(field, value, valueStr, strict, layout)
+	}
+	if field.Type() != timeType && reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		return assignText(field, valueStr, strict)
+	}
+
+	var err error
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(valueStr)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(valueStr, 10, 64); err == nil {
+			field.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(valueStr, 10, 64); err == nil {
+			field.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(valueStr, 64); err == nil {
+			field.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(valueStr); err == nil {
+			field.SetBool(b)
+		}
+	default:
+		if field.Type() == hyperlinkType {
+			field.Set(reflect.ValueOf(parseHyperlink(valueStr)))
+		} else if field.Type() == timeType {
+			var t time.Time
+			if t, err = parseTime(valueStr, layout); err == nil {
+				field.Set(reflect.ValueOf(t))
+			}
+		} else if k := field.Kind(); (k == reflect.Struct || k == reflect.Slice || k == reflect.Map) && valueStr != "" {
+			err = assignJSON(field, value)
+		}
+	}
+
+	if err != nil && strict && valueStr != "" {
+		return fmt.Errorf("cannot convert %q to %s", valueStr, field.Type())
+	}
+	return nil
+}
+
+// assignText decodes a field whose type implements
+// encoding.TextUnmarshaler, such as a UUID, from the cell's text. Blank
+// cells, and text it rejects, leave the field unchanged.
+func assignText(field reflect.Value, valueStr string, strict bool) error {
+	if valueStr == "" {
+		return nil
+	}
+	decoded := reflect.New(field.Type())
+	if err := decoded.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(valueStr)); err != nil {
+		if strict {
+			return fmt.Errorf("cannot convert %q to %s", valueStr, field.Type())
+		}
+		return nil
+	}
+	field.Set(decoded.Elem())
+	return nil
+}
+
+// assignJSON decodes a slice, map or struct field from its cell. Cells
+// hold the JSON text fieldValue writes; a value that isn't a string, such
+// as one set directly by a caller, is converted through JSON as well.
+func assignJSON(field reflect.Value, value interface{}) error {
+	data, ok := value.(string)
+	if !ok {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+
+	decoded := reflect.New(field.Type())
+	if err := json.Unmarshal([]byte(data), decoded.Interface()); err != nil {
+		return err
+	}
+	field.Set(decoded.Elem())
+	return nil
+}
+
+// assignRaw copies the whole row into a []interface{} or []string field.
+// nil cells become empty strings in a []string.
+func assignRaw(field reflect.Value, row []interface{}) error {
+	if !field.CanSet() {
+		return nil
+	}
+
+	switch field.Interface().(type) {
+	case []interface{}:
+		field.Set(reflect.ValueOf(append([]interface{}{}, row...)))
+	case []string:
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if cell != nil {
+				cells[i] = formatCell(cell)
+			}
+		}
+		field.Set(reflect.ValueOf(cells))
+	default:
+		return fmt.Errorf("raw field must be []string or []interface{}, not %s", field.Type())
+	}
+	return nil
+}
+
+// parseTime parses a time cell with layout, or when layout is empty as
+// RFC 3339 with a fallback to a plain 2006-01-02 date.
+func parseTime(s, layout string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if layout != "" {
+		return time.Parse(layout, s)
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", s)
+	}
+	return t, err
+}
+
+// sheetDateTimeLayouts are the default ways Sheets displays a date-time.
+var sheetDateTimeLayouts = []string{
+	"1/2/2006 15:04:05",
+	"2006-01-02 15:04:05",
+	"1/2/2006",
+}
+
+// sheetEpoch is day 0 of the sheet's date serial numbers.
+var sheetEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseSheetTime parses a date-time cell written by a formula such as
+// =NOW(): a serial number (days since 1899-12-30) or one of the default
+// display formats. The sheet's time zone isn't known, so the time is read
+// as UTC.
+func parseSheetTime(cell interface{}) (time.Time, bool) {
+	if f, ok := cell.(float64); ok {
+		return serialTime(f)
+	}
+
+	s := strings.TrimSpace(formatCell(cell))
+	for _, layout := range sheetDateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return serialTime(f)
+	}
+	return time.Time{}, false
+}
+
+// maxSerialDay is 9999-12-31, the last date a sheet can hold.
+const maxSerialDay = 2958465
+
+// serialTime converts a date serial number to a time, to the second.
+func serialTime(days float64) (time.Time, bool) {
+	if math.IsNaN(days) || days < 0 || days > maxSerialDay+1 {
+		return time.Time{}, false
+	}
+	secs := math.Round(days * 24 * 60 * 60)
+	return sheetEpoch.Add(time.Duration(secs) * time.Second), true
+}
+
+// formatCell stringifies a cell value. Numbers are written out in full
+// rather than in exponent form, so 1000000 doesn't become "1e+06".
+func formatCell(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// assignPointer allocates and populates a pointer field. Empty cells, and
+// in lenient mode values that can't be converted, leave it nil.
+func assignPointer(field reflect.Value, value interface{}, valueStr string, strict bool, layout string) error {
+	field.Set(reflect.Zero(field.Type()))
+	if valueStr == "" {
+		return nil
+	}
+
+	elem := reflect.New(field.Type().Elem())
+	if err := assignField(elem.Elem(), value, true, layout); err != nil {
+		if strict {
+			return err
+		}
+		return nil
+	}
+	field.Set(elem)
+	return nil
+}
@@ -1,8 +1,16 @@
 package quire
 
 import (
+	"context"
+	"errors"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
 )
 
 func TestStructSliceToValues(t *testing.T) {
@@ -30,6 +38,23 @@ func TestStructSliceToValues(t *testing.T) {
 			records: []TestUser{},
 			wantErr: false,
 		},
+		{
+			name:    "pointer to slice",
+			records: &[]TestUser{{ID: 1, Name: "Alice"}},
+			wantErr: false,
+		},
+		{
+			name:        "pointer to struct",
+			records:     &TestUser{ID: 1, Name: "Alice"},
+			wantErr:     true,
+			expectedErr: "records must be a slice",
+		},
+		{
+			name:        "nil pointer to slice",
+			records:     (*[]TestUser)(nil),
+			wantErr:     true,
+			expectedErr: "records must be a slice",
+		},
 	}
 
 	for _, tt := range tests {
@@ -327,6 +352,27 @@ func TestSetField(t *testing.T) {
 			expected:  uint(100),
 			expectSet: true,
 		},
+		{
+			name:      "set string field from large number",
+			field:     reflect.ValueOf(new(string)).Elem(),
+			value:     1000000.0,
+			expected:  "1000000",
+			expectSet: true,
+		},
+		{
+			name:      "set string field from fractional number",
+			field:     reflect.ValueOf(new(string)).Elem(),
+			value:     1234567.25,
+			expected:  "1234567.25",
+			expectSet: true,
+		},
+		{
+			name:      "set int field from large float",
+			field:     reflect.ValueOf(new(int)).Elem(),
+			value:     25000000.0,
+			expected:  25000000,
+			expectSet: true,
+		},
 		{
 			name:      "invalid int value",
 			field:     reflect.ValueOf(new(int)).Elem(),
@@ -373,3 +419,896 @@ func TestSetField_CannotSet(t *testing.T) {
 		t.Errorf("setField() unexpected error = %v", err)
 	}
 }
+
+func TestScanner_StrictCollectsErrors(t *testing.T) {
+	headers := []interface{}{"ID", "Name", "Email", "Age"}
+	rows := [][]interface{}{
+		{1.0, "Alice", "alice@test.com", "thirty"},
+		{2.0, "Bob", "bob@test.com", 25.0},
+		{"two", "Charlie", "charlie@test.com", "old"},
+		{4.0, "Diana", "diana@test.com", ""},
+	}
+
+	var users []TestUser
+	err := scanner{strict: true}.scanIntoSlice(rows, headers, &users)
+
+	var scanErrs ScanErrors
+	if !errors.As(err, &scanErrs) {
+		t.Fatalf("scanIntoSlice() error = %v, want ScanErrors", err)
+	}
+
+	type location struct {
+		row   int
+		field string
+	}
+	var got []location
+	for _, e := range scanErrs {
+		got = append(got, location{e.Row, e.Field})
+	}
+	want := []location{{0, "Age"}, {2, "ID"}, {2, "Age"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanErrors locations = %v, want %v", got, want)
+	}
+
+	if len(users) != 4 {
+		t.Fatalf("scanIntoSlice() scanned %d rows, want all 4", len(users))
+	}
+	if users[2].Name != "Charlie" || users[2].ID != 0 {
+		t.Errorf("row with bad cells = %+v, want valid fields set and bad ones zero", users[2])
+	}
+}
+
+func TestScanner_LenientIgnoresErrors(t *testing.T) {
+	headers := []interface{}{"ID", "Age"}
+	rows := [][]interface{}{{"one", "thirty"}}
+
+	var users []TestUser
+	if err := scanIntoSlice(rows, headers, &users); err != nil {
+		t.Errorf("scanIntoSlice() unexpected error = %v", err)
+	}
+}
+
+func TestQuery_StrictScan(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Age"},
+				{1.0, "Alice", "abc"},
+				{2.0, "Bob", "xyz"},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	var users []TestUser
+	err := table.Query().StrictScan().Get(context.Background(), &users)
+
+	var scanErrs ScanErrors
+	if !errors.As(err, &scanErrs) || len(scanErrs) != 2 {
+		t.Fatalf("Get() error = %v, want 2 ScanErrors", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("Get() scanned %d rows, want 2", len(users))
+	}
+}
+
+type OptionalUser struct {
+	Name   string  `quire:"Name"`
+	Age    *int    `quire:"Age"`
+	Nick   *string `quire:"Nick"`
+	Active *bool   `quire:"Active"`
+}
+
+func TestScanRow_PointerFields(t *testing.T) {
+	headers := []interface{}{"Name", "Age", "Nick", "Active"}
+
+	tests := []struct {
+		name       string
+		row        []interface{}
+		wantAge    *int
+		wantNick   *string
+		wantActive *bool
+	}{
+		{
+			name:       "present",
+			row:        []interface{}{"Alice", 30.0, "Al", "TRUE"},
+			wantAge:    ptr(30),
+			wantNick:   ptr("Al"),
+			wantActive: ptr(true),
+		},
+		{
+			name:       "zero values are not nil",
+			row:        []interface{}{"Bob", 0.0, "", false},
+			wantAge:    ptr(0),
+			wantActive: ptr(false),
+		},
+		{
+			name: "empty cells",
+			row:  []interface{}{"Carol", "", "", ""},
+		},
+		{
+			name: "missing columns",
+			row:  []interface{}{"Dave"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u OptionalUser
+			if err := scanRow(tt.row, headers, reflect.ValueOf(&u)); err != nil {
+				t.Fatalf("scanRow() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(u.Age, tt.wantAge) {
+				t.Errorf("Age = %v, want %v", u.Age, tt.wantAge)
+			}
+			if !reflect.DeepEqual(u.Nick, tt.wantNick) {
+				t.Errorf("Nick = %v, want %v", u.Nick, tt.wantNick)
+			}
+			if !reflect.DeepEqual(u.Active, tt.wantActive) {
+				t.Errorf("Active = %v, want %v", u.Active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestScanRow_PointerFieldInvalid(t *testing.T) {
+	headers := []interface{}{"Age"}
+	row := []interface{}{"unknown"}
+
+	var u OptionalUser
+	if err := scanRow(row, headers, reflect.ValueOf(&u)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if u.Age != nil {
+		t.Errorf("Age = %v, want nil for unparseable cell", *u.Age)
+	}
+
+	err := scanner{strict: true}.scanRow(row, headers, reflect.ValueOf(&u))
+	var scanErrs ScanErrors
+	if !errors.As(err, &scanErrs) || len(scanErrs) != 1 {
+		t.Errorf("strict scanRow() error = %v, want one ScanError", err)
+	}
+}
+
+func TestStructToValues_PointerFields(t *testing.T) {
+	values, err := structToValues(OptionalUser{Name: "Alice", Age: ptr(30)})
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+
+	want := []interface{}{"Alice", 30, "", ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("structToValues() = %v, want %v", values, want)
+	}
+}
+
+type Event struct {
+	Name      string     `quire:"Name"`
+	CreatedAt time.Time  `quire:"CreatedAt"`
+	Day       time.Time  `quire:"Day,time:02/01/2006"`
+	DueAt     *time.Time `quire:"DueAt"`
+}
+
+func TestTimeFields_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	day := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+	in := Event{Name: "launch", CreatedAt: created, Day: day}
+
+	values, err := structToValues(in)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{"launch", "2024-03-05T14:30:00Z", "06/03/2024", ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("structToValues() = %v, want %v", values, want)
+	}
+
+	headers := []interface{}{"Name", "CreatedAt", "Day", "DueAt"}
+	var out Event
+	if err := scanRow(values, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if !out.CreatedAt.Equal(created) || !out.Day.Equal(day) || out.DueAt != nil {
+		t.Errorf("scanRow() = %+v, want %+v", out, in)
+	}
+}
+
+func TestTimeFields_Parse(t *testing.T) {
+	headers := []interface{}{"CreatedAt", "Day", "DueAt"}
+
+	tests := []struct {
+		name    string
+		row     []interface{}
+		created time.Time
+		day     time.Time
+		due     *time.Time
+	}{
+		{
+			name:    "plain date fallback",
+			row:     []interface{}{"2024-01-02", "31/12/2023", "2024-02-01"},
+			created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			day:     time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			due:     ptr(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name: "unparseable cells stay zero",
+			row:  []interface{}{"yesterday", "2023-12-31", "later"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e Event
+			if err := scanRow(tt.row, headers, reflect.ValueOf(&e)); err != nil {
+				t.Fatalf("scanRow() unexpected error = %v", err)
+			}
+			if !e.CreatedAt.Equal(tt.created) {
+				t.Errorf("CreatedAt = %v, want %v", e.CreatedAt, tt.created)
+			}
+			if !e.Day.Equal(tt.day) {
+				t.Errorf("Day = %v, want %v", e.Day, tt.day)
+			}
+			if !reflect.DeepEqual(e.DueAt, tt.due) {
+				t.Errorf("DueAt = %v, want %v", e.DueAt, tt.due)
+			}
+		})
+	}
+
+	var e Event
+	err := scanner{strict: true}.scanRow([]interface{}{"yesterday"}, headers, reflect.ValueOf(&e))
+	if err == nil {
+		t.Error("strict scanRow() expected error for unparseable time")
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	type sample struct {
+		Plain    string
+		Named    string `quire:"Full Name"`
+		Skipped  string `quire:"-"`
+		Options  int    `quire:"Score,omitempty,readonly"`
+		Letter   string `quire:"Notes,col:D"`
+		Number   string `quire:",col:3"`
+		BadCol   string `quire:"Bad,col:0"`
+		Unknown  string `quire:"Other,frobnicate"`
+		Layout   string `quire:"Day,time:2006-01-02"`
+		Combined string `quire:"X,col:AA,omitempty"`
+		Enum     int    `quire:"State,enummap=on:1|off:0|bad"`
+		Now      string `quire:"Stamp,now"`
+		Key      int    `quire:"ID,pk"`
+	}
+
+	want := []fieldTag{
+		{name: "Plain", column: -1},
+		{name: "Full Name", column: -1},
+		{skip: true},
+		{name: "Score", omitEmpty: true, readOnly: true, column: -1},
+		{name: "Notes", column: 3},
+		{name: "Number", column: 2},
+		{name: "Bad", column: -1},
+		{name: "Other", column: -1},
+		{name: "Day", column: -1, timeLayout: "2006-01-02"},
+		{name: "X", omitEmpty: true, column: 26},
+		{name: "State", column: -1, enum: []enumLabel{{"on", 1}, {"off", 0}}},
+		{name: "Stamp", column: -1, now: true},
+		{name: "ID", column: -1, pk: true},
+	}
+
+	typ := reflect.TypeOf(sample{})
+	for i := 0; i < typ.NumField(); i++ {
+		if got := parseTag(typ.Field(i)); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("parseTag(%s) = %+v, want %+v", typ.Field(i).Name, got, want[i])
+		}
+	}
+}
+
+type TaggedRecord struct {
+	ID      int    `quire:"ID"`
+	Name    string `quire:"Name,omitempty"`
+	Total   string `quire:"Total,readonly"`
+	Email   string `quire:"Email"`
+	Comment string `quire:"Comment,col:F"`
+}
+
+func TestStructToValues_TagOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		record TaggedRecord
+		want   []interface{}
+	}{
+		{
+			name:   "all set",
+			record: TaggedRecord{ID: 1, Name: "Alice", Total: "=SUM(A1:A9)", Email: "a@test.com", Comment: "vip"},
+			want:   []interface{}{1, "Alice", nil, "a@test.com", nil, "vip"},
+		},
+		{
+			name:   "omitempty leaves a hole",
+			record: TaggedRecord{ID: 2, Email: "b@test.com"},
+			want:   []interface{}{2, nil, nil, "b@test.com", nil, ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := structToValues(tt.record)
+			if err != nil {
+				t.Fatalf("structToValues() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("structToValues() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanRow_TagOptions(t *testing.T) {
+	headers := []interface{}{"ID", "Name", "Total", "Email", "Extra", "Remarks"}
+	row := []interface{}{3.0, "Carol", "42", "c@test.com", "x", "note"}
+
+	var got TaggedRecord
+	if err := scanRow(row, headers, reflect.ValueOf(&got)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+
+	// readonly fields are still read; col:F reads column F whatever its header.
+	want := TaggedRecord{ID: 3, Name: "Carol", Total: "42", Email: "c@test.com", Comment: "note"}
+	if got != want {
+		t.Errorf("scanRow() = %+v, want %+v", got, want)
+	}
+}
+
+type RawRecord struct {
+	ID    int           `quire:"ID"`
+	Name  string        `quire:"Name"`
+	Raw   []interface{} `quire:",raw"`
+	Cells []string      `quire:"Anything,raw"`
+}
+
+func TestScanRow_RawField(t *testing.T) {
+	headers := []interface{}{"ID", "Name", "Unmapped", "Score"}
+	row := []interface{}{7.0, "Grace", nil, 1500000.0}
+
+	var got RawRecord
+	if err := scanRow(row, headers, reflect.ValueOf(&got)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+
+	if got.ID != 7 || got.Name != "Grace" {
+		t.Errorf("scanRow() typed fields = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Raw, row) {
+		t.Errorf("Raw = %v, want %v", got.Raw, row)
+	}
+	if want := []string{"7", "Grace", "", "1500000"}; !reflect.DeepEqual(got.Cells, want) {
+		t.Errorf("Cells = %q, want %q", got.Cells, want)
+	}
+
+	values, err := structToValues(got)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	if want := []interface{}{7, "Grace"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("structToValues() = %v, want raw fields left out", values)
+	}
+}
+
+func TestScanRow_RawFieldWrongType(t *testing.T) {
+	var dest struct {
+		Raw string `quire:",raw"`
+	}
+	if err := scanRow([]interface{}{"x"}, []interface{}{"A"}, reflect.ValueOf(&dest)); err == nil {
+		t.Error("scanRow() expected error for a raw field that isn't a slice")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+type Status int
+
+const (
+	StatusInactive Status = iota
+	StatusActive
+	StatusBanned
+)
+
+type Account struct {
+	Name   string  `quire:"Name"`
+	Status Status  `quire:"Status,enummap=active:1|inactive:0"`
+	Prev   *Status `quire:"Prev,enummap=active:1|inactive:0"`
+}
+
+func TestEnumMap_RoundTrip(t *testing.T) {
+	in := Account{Name: "alice", Status: StatusActive, Prev: ptr(StatusInactive)}
+
+	values, err := structToValues(in)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{"alice", "active", "inactive"}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("structToValues() = %v, want %v", values, want)
+	}
+
+	headers := []interface{}{"Name", "Status", "Prev"}
+	var out Account
+	if err := scanRow(values, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("scanRow() = %+v, want %+v", out, in)
+	}
+}
+
+type Listing struct {
+	Name   string         `quire:"Name"`
+	Tags   []string       `quire:"Tags"`
+	Stock  map[string]int `quire:"Stock"`
+	Origin Address        `quire:"Origin"`
+}
+
+type Address struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+func TestJSONFields_RoundTrip(t *testing.T) {
+	in := Listing{
+		Name:   "lamp",
+		Tags:   []string{"home", "light", "sale"},
+		Stock:  map[string]int{"north": 3, "south": 0},
+		Origin: Address{City: "Lyon", Country: "FR"},
+	}
+
+	values, err := structToValues(in)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{
+		"lamp",
+		`["home","light","sale"]`,
+		`{"north":3,"south":0}`,
+		`{"city":"Lyon","country":"FR"}`,
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("structToValues() = %v, want %v", values, want)
+	}
+
+	headers := []interface{}{"Name", "Tags", "Stock", "Origin"}
+	var out Listing
+	if err := scanRow(values, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("scanRow() = %+v, want %+v", out, in)
+	}
+}
+
+func TestJSONFields_EmptyAndInvalid(t *testing.T) {
+	values, err := structToValues(Listing{Name: "empty"})
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{"empty", "", "", `{"city":"","country":""}`}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("structToValues() = %v, want %v", values, want)
+	}
+
+	headers := []interface{}{"Name", "Tags", "Stock", "Origin"}
+	row := []interface{}{"bad", "[a b c]", "", "not json"}
+
+	var out Listing
+	if err := scanRow(row, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if out.Tags != nil || out.Stock != nil || out.Origin != (Address{}) {
+		t.Errorf("scanRow() = %+v, want unparseable and blank cells left zero", out)
+	}
+
+	err = scanner{strict: true}.scanRow(row, headers, reflect.ValueOf(&out))
+	var scanErrs ScanErrors
+	if !errors.As(err, &scanErrs) || len(scanErrs) != 2 {
+		t.Errorf("strict scanRow() error = %v, want ScanErrors for Tags and Origin", err)
+	}
+}
+
+type Session struct {
+	ID     uuid.UUID  `quire:"ID"`
+	Parent *uuid.UUID `quire:"Parent"`
+	User   string     `quire:"User"`
+}
+
+func TestUUIDFields_RoundTrip(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	parent := uuid.MustParse("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	in := Session{ID: id, Parent: &parent, User: "alice"}
+
+	values, err := structToValues(in)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{id.String(), parent.String(), "alice"}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("structToValues() = %v, want %v", values, want)
+	}
+
+	headers := []interface{}{"ID", "Parent", "User"}
+	var out Session
+	if err := scanRow(values, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("scanRow() = %+v, want %+v", out, in)
+	}
+
+	// Sheets may return the canonical form in upper case.
+	row := []interface{}{strings.ToUpper(id.String()), "", "bob"}
+	out = Session{}
+	if err := scanRow(row, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if out.ID != id || out.Parent != nil {
+		t.Errorf("scanRow() = %+v, want ID %s and a nil Parent", out, id)
+	}
+}
+
+func TestUUIDFields_Invalid(t *testing.T) {
+	headers := []interface{}{"ID", "Parent", "User"}
+	row := []interface{}{"not-a-uuid", "", "carol"}
+
+	var out Session
+	if err := scanRow(row, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if out.ID != uuid.Nil || out.User != "carol" {
+		t.Errorf("scanRow() = %+v, want a nil ID", out)
+	}
+
+	err := scanner{strict: true}.scanRow(row, headers, reflect.ValueOf(&out))
+	var scanErrs ScanErrors
+	if !errors.As(err, &scanErrs) || len(scanErrs) != 1 || scanErrs[0].Field != "ID" {
+		t.Errorf("strict scanRow() error = %v, want a ScanError for ID", err)
+	}
+}
+
+func TestEnumMap_Unmapped(t *testing.T) {
+	// Values without a label are written as numbers and read back as such.
+	values, err := structToValues(Account{Name: "bob", Status: StatusBanned})
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	if values[1] != StatusBanned {
+		t.Errorf("Status cell = %v, want %v", values[1], StatusBanned)
+	}
+
+	headers := []interface{}{"Name", "Status"}
+	var a Account
+	if err := scanRow([]interface{}{"bob", 2.0}, headers, reflect.ValueOf(&a)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if a.Status != StatusBanned {
+		t.Errorf("Status = %v, want %v", a.Status, StatusBanned)
+	}
+
+	err = scanner{strict: true}.scanRow([]interface{}{"bob", "suspended"}, headers, reflect.ValueOf(&a))
+	if err == nil {
+		t.Error("strict scanRow() expected error for unknown label")
+	}
+}
+
+type Base struct {
+	ID int `quire:"ID"`
+}
+
+type Audited struct {
+	Base
+	CreatedBy string `quire:"CreatedBy"`
+	Note      string `quire:"Note"`
+}
+
+type Meta struct {
+	Source string `quire:"Source"`
+}
+
+type Document struct {
+	Audited
+	*Meta
+	Title string `quire:"Title"`
+	Note  string `quire:"Note"` // shadows Audited.Note
+}
+
+func TestEmbeddedStructs_RoundTrip(t *testing.T) {
+	in := Document{
+		Audited: Audited{Base: Base{ID: 7}, CreatedBy: "alice", Note: "inner"},
+		Meta:    &Meta{Source: "import"},
+		Title:   "Spec",
+		Note:    "outer",
+	}
+
+	values, err := structToValues(in)
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	want := []interface{}{7, "alice", "import", "Spec", "outer"}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("structToValues() = %v, want %v", values, want)
+	}
+
+	headers := []interface{}{"ID", "CreatedBy", "Source", "Title", "Note"}
+	var out Document
+	if err := scanRow(values, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+
+	in.Audited.Note = "" // shadowed, so never read
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("scanRow() = %+v (meta %+v), want %+v", out, out.Meta, in)
+	}
+}
+
+func TestEmbeddedStructs_NilPointer(t *testing.T) {
+	values, err := structToValues(Document{Title: "Draft"})
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	// The nil *Meta keeps its column as a hole.
+	want := []interface{}{0, "", nil, "Draft", ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("structToValues() = %#v, want %#v", values, want)
+	}
+
+	headers := []interface{}{"ID", "Title"}
+	var out Document
+	if err := scanRow([]interface{}{1.0, "Draft"}, headers, reflect.ValueOf(&out)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if out.Meta != nil {
+		t.Errorf("scanRow() allocated Meta = %+v without a Source column", out.Meta)
+	}
+
+	row, err := fieldNaming{}.structToHeaderValues(Document{Title: "Draft"}, []interface{}{"Source", "Title"}, false)
+	if err != nil {
+		t.Fatalf("structToHeaderValues() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(row, []interface{}{nil, "Draft"}) {
+		t.Errorf("structToHeaderValues() = %#v, want Source left nil", row)
+	}
+}
+
+// snakeCase turns a Go field name such as "UserID" into "user_id".
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+type Profile struct {
+	UserID      int
+	DisplayName string
+	Email       string `quire:"E-mail"`
+	Plan        string `quire:",omitempty"`
+}
+
+func TestDefaultTagFunc(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"user_id", "display_name", "E-mail", "plan"},
+				{1.0, "Alice", "alice@test.com", "pro"},
+				{2.0, "Bob", "bob@test.com", "free"},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+	db := &DB{client: mock, defaultTagFunc: snakeCase}
+
+	var accounts []Profile
+	if err := db.Table("Accounts").Query().Where("user_id", "=", 2).Get(ctx, &accounts); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	want := []Profile{{UserID: 2, DisplayName: "Bob", Email: "bob@test.com", Plan: "free"}}
+	if !reflect.DeepEqual(accounts, want) {
+		t.Errorf("Get() = %+v, want %+v", accounts, want)
+	}
+
+	err := db.Table("Accounts").Upsert(ctx, "user_id", Profile{UserID: 1, DisplayName: "Alicia"})
+	if err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+	if len(mock.BatchWriteCalls) != 1 {
+		t.Fatalf("Upsert() made %d batch writes, want 1", len(mock.BatchWriteCalls))
+	}
+	wantWrite := map[string][][]interface{}{"Accounts!A2:C2": {{1, "Alicia", ""}}}
+	if got := mock.BatchWriteCalls[0].Data; !reflect.DeepEqual(got, wantWrite) {
+		t.Errorf("Upsert() wrote %+v, want %+v", got, wantWrite)
+	}
+
+	if got := snakeCase("UserID"); got != "user_id" {
+		t.Errorf("snakeCase(UserID) = %q, want user_id", got)
+	}
+}
+
+func TestDefaultTagFunc_Unset(t *testing.T) {
+	var fields []string
+	for _, sf := range structFields(reflect.TypeOf(Profile{})) {
+		fields = append(fields, sf.tag.name)
+	}
+	want := []string{"UserID", "DisplayName", "E-mail", "Plan"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("structFields() names = %v, want %v", fields, want)
+	}
+
+	named := fieldNaming{fn: snakeCase}.structFields(reflect.TypeOf(Profile{}))
+	if named[2].tag.name != "E-mail" || named[3].tag.name != "plan" {
+		t.Errorf("DefaultTagFunc renamed %q and %q, want tagged name kept and options-only tag renamed",
+			named[2].tag.name, named[3].tag.name)
+	}
+}
+
+type AuditEntry struct {
+	Action    string     `quire:"Action"`
+	CreatedAt time.Time  `quire:"CreatedAt,now"`
+	SeenAt    *time.Time `quire:"SeenAt,now"`
+}
+
+func TestNowField_Write(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	records := []AuditEntry{
+		{Action: "login"},
+		{Action: "import", CreatedAt: at, SeenAt: &at},
+	}
+
+	rows, err := structSliceToValues(records)
+	if err != nil {
+		t.Fatalf("structSliceToValues() unexpected error = %v", err)
+	}
+	want := [][]interface{}{
+		{"login", "=NOW()", "=NOW()"},
+		{"import", "2024-03-01T09:30:00Z", "2024-03-01T09:30:00Z"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("structSliceToValues() = %v, want %v", rows, want)
+	}
+
+	// Updates keep the timestamp the row was inserted with.
+	row, err := structToValues(AuditEntry{Action: "logout"})
+	if err != nil {
+		t.Fatalf("structToValues() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(row, []interface{}{"logout", nil, nil}) {
+		t.Errorf("structToValues() = %v, want now fields left nil", row)
+	}
+}
+
+func TestNowField_Scan(t *testing.T) {
+	headers := []interface{}{"Action", "CreatedAt", "SeenAt"}
+	want := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cell interface{}
+	}{
+		{"displayed", "3/1/2024 9:30:00"},
+		{"iso displayed", "2024-03-01 09:30:00"},
+		{"serial number", 45352.395833333336},
+		{"serial text", "45352.395833333336"},
+		{"rfc3339", "2024-03-01T09:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e AuditEntry
+			if err := scanRow([]interface{}{"login", tt.cell, tt.cell}, headers, reflect.ValueOf(&e)); err != nil {
+				t.Fatalf("scanRow() unexpected error = %v", err)
+			}
+			if !e.CreatedAt.Equal(want) || e.SeenAt == nil || !e.SeenAt.Equal(want) {
+				t.Errorf("scanRow() = %v, %v, want %v", e.CreatedAt, e.SeenAt, want)
+			}
+		})
+	}
+
+	var e AuditEntry
+	if err := scanRow([]interface{}{"login", "=NOW()", ""}, headers, reflect.ValueOf(&e)); err != nil {
+		t.Fatalf("scanRow() unexpected error = %v", err)
+	}
+	if !e.CreatedAt.IsZero() || e.SeenAt != nil {
+		t.Errorf("scanRow() of formula text = %v, %v, want zero values", e.CreatedAt, e.SeenAt)
+	}
+}
+
+func TestStructFields_Cache(t *testing.T) {
+	types := []reflect.Type{
+		reflect.TypeOf(TestUser{}),
+		reflect.TypeOf(OptionalUser{}),
+		reflect.TypeOf(TaggedRecord{}),
+		reflect.TypeOf(RawRecord{}),
+		reflect.TypeOf(Account{}),
+		reflect.TypeOf(Document{}),
+		reflect.TypeOf(Profile{}),
+		reflect.TypeOf(AuditEntry{}),
+	}
+
+	for _, typ := range types {
+		for _, naming := range []fieldNaming{{}, {fn: snakeCase}, {fn: snakeCase, fields: new(sync.Map)}} {
+			uncached := naming.resolveFields(walkFields(typ))
+			first := naming.structFields(typ)
+			second := naming.structFields(typ)
+			if !reflect.DeepEqual(first, uncached) || !reflect.DeepEqual(second, uncached) {
+				t.Errorf("structFields(%s) = %+v then %+v, want %+v", typ, first, second, uncached)
+			}
+		}
+
+		a, b := structFields(typ), structFields(typ)
+		if len(a) > 0 && &a[0] != &b[0] {
+			t.Errorf("structFields(%s) walked the type again instead of using the cache", typ)
+		}
+	}
+
+	// Renaming untagged fields must not leak into the shared cache.
+	fieldNaming{fn: strings.ToUpper, fields: new(sync.Map)}.structFields(reflect.TypeOf(Profile{}))
+	if got := structFields(reflect.TypeOf(Profile{}))[0].tag.name; got != "UserID" {
+		t.Errorf("structFields() after a naming function = %q, want UserID", got)
+	}
+
+	// A DB's naming resolves each type once, apart from other DBs'.
+	snake := (&DB{defaultTagFunc: snakeCase}).Table("Accounts").naming()
+	upper := (&DB{defaultTagFunc: strings.ToUpper}).Table("Accounts").naming()
+	typ := reflect.TypeOf(Profile{})
+	a, b := snake.structFields(typ), snake.structFields(typ)
+	if &a[0] != &b[0] {
+		t.Error("structFields() with a DefaultTagFunc resolved the type again instead of using the cache")
+	}
+	if got := upper.structFields(typ)[0].tag.name; got != "USERID" {
+		t.Errorf("structFields() for another DB = %q, want USERID", got)
+	}
+}
+
+func BenchmarkQuery_Get(b *testing.B) {
+	data := [][]interface{}{{"ID", "Name", "Email", "Age"}}
+	for i := 0; i < 10000; i++ {
+		data = append(data, []interface{}{float64(i), "User", "user@test.com", 30.0})
+	}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return data, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var users []TestUser
+		if err := table.Query().Get(ctx, &users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStructSliceToValues(b *testing.B) {
+	users := make([]TestUser, 10000)
+	for i := range users {
+		users[i] = TestUser{ID: i, Name: "User", Email: "user@test.com", Age: 30}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := structSliceToValues(users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,16 +4,301 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Table represents a sheet (table) within the spreadsheet.
 type Table struct {
 	db   *DB
 	name string
+	opts TableOptions
+
+	mu          sync.Mutex
+	seenHeaders []interface{}
+
+	// snapshot, if non-nil, is the sheet as fetched by DB.Tables; queries
+	// read it instead of calling the API.
+	snapshot [][]interface{}
+}
+
+// TableOptions configures a Table handle created with DB.TableWithOptions.
+type TableOptions struct {
+	// Anchor is the A1 reference of the table's top-left header cell, for
+	// sheets where the table doesn't start at A1 (e.g. "B3" below a title
+	// block). Empty or invalid values mean "A1".
+	Anchor string
+
+	// MaxScanColumn bounds reads to columns up to and including this one
+	// (e.g. "Z"), so very wide sheets don't transfer unused columns.
+	// Empty or invalid values read every column.
+	MaxScanColumn string
+
+	// DetectHeaderChanges makes queries compare the header row with the one
+	// seen by the previous query on this handle and return ErrHeaderChanged
+	// when it differs. The new header is remembered, so retrying succeeds.
+	DetectHeaderChanges bool
+
+	// Key is the primary-key column Find, UpdateByKey and DeleteByKey look
+	// rows up by. If empty, they use the column of the struct field tagged
+	// "pk".
+	Key string
+}
+
+// WithHeaderRow returns a handle for the same sheet that treats row n
+// (1-based) as the header row, for sheets with a title or banner above
+// the headers. Rows above it are ignored and the rows below it are the
+// data, for reads and writes alike. It keeps the handle's other options,
+// including the anchor column; n below 1 means row 1.
+func (t *Table) WithHeaderRow(n int) *Table {
+	col, _ := t.anchor()
+	opts := t.opts
+	opts.Anchor = columnIndexToLetter(col) + strconv.Itoa(max(n, 1))
+	return t.withOptions(opts)
+}
+
+// withOptions returns a handle for the same sheet, and snapshot if any,
+// configured with opts.
+func (t *Table) withOptions(opts TableOptions) *Table {
+	return &Table{
+		db:       t.db,
+		name:     t.name,
+		opts:     opts,
+		snapshot: t.snapshot,
+	}
+}
+
+// anchor returns the 0-based column and 1-based row of the table's header
+// cell, parsed from TableOptions.Anchor.
+func (t *Table) anchor() (col, row int) {
+	col, row, ok := parseCellRef(t.opts.Anchor)
+	if !ok {
+		return 0, 1
+	}
+	return col, row
+}
+
+// parseCellRef parses an A1 cell reference such as "B3". A bare column
+// ("B") or row ("3") is accepted, defaulting the other part to A or 1.
+func parseCellRef(ref string) (col, row int, ok bool) {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	letters := strings.TrimRightFunc(ref, func(r rune) bool { return r >= '0' && r <= '9' })
+	digits := ref[len(letters):]
+	if ref == "" || strings.TrimFunc(letters, func(r rune) bool { return r >= 'A' && r <= 'Z' }) != "" {
+		return 0, 0, false
+	}
+
+	for _, r := range letters {
+		col = col*26 + int(r-'A') + 1
+	}
+	col--
+	if col < 0 {
+		col = 0
+	}
+
+	row = 1
+	if digits != "" {
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 1 {
+			return 0, 0, false
+		}
+		row = n
+	}
+	return col, row, true
+}
+
+// readData reads the sheet and returns the table's cells starting at the
+// header row and anchor column.
+func (t *Table) readData(ctx context.Context) ([][]interface{}, error) {
+	range_ := t.name
+	if last, ok := t.maxScanColumn(); ok {
+		range_ = fmt.Sprintf("%s!A:%s", t.name, last)
+	}
+
+	data, err := t.db.client.Read(ctx, range_)
+	if err != nil {
+		return nil, err
+	}
+	return t.prepareData(data), nil
+}
+
+// maxScanColumn returns the column letter from TableOptions.MaxScanColumn,
+// if it is set and valid.
+func (t *Table) maxScanColumn() (string, bool) {
+	if t.opts.MaxScanColumn == "" {
+		return "", false
+	}
+	col := parseColumn(strings.ToUpper(strings.TrimSpace(t.opts.MaxScanColumn)))
+	if col < 0 {
+		return "", false
+	}
+	return columnIndexToLetter(col), true
+}
+
+// queryData returns the data queries run against: the snapshot taken by
+// DB.Tables if there is one, or a fresh read.
+func (t *Table) queryData(ctx context.Context) ([][]interface{}, error) {
+	if t.snapshot != nil {
+		return t.prepareData(t.snapshot), nil
+	}
+	return t.readData(ctx)
+}
+
+// prepareData turns a sheet read into the table's rows: trimmed to the
+// anchor, with the header row normalized if Config.NormalizeHeaders is set
+// and the data rows padded to a common width.
+func (t *Table) prepareData(data [][]interface{}) [][]interface{} {
+	data = t.trimToAnchor(data)
+	if t.db.normalizeHeaders && len(data) > 0 {
+		data = append([][]interface{}{normalizeHeaders(data[0])}, data[1:]...)
+	}
+	return padRows(data)
+}
+
+// padRows pads the data rows below the header with nil cells to the width
+// of the header or the widest row, whichever is wider. The API leaves out
+// trailing blank cells, so rows come back ragged, especially where an
+// array formula spills a varying number of columns; padding gives every
+// row the same shape. Short rows are copied rather than extended in place,
+// as data may be a shared snapshot. The header row is left as it is.
+func padRows(data [][]interface{}) [][]interface{} {
+	if len(data) < 2 {
+		return data
+	}
+
+	width := 0
+	for _, row := range data {
+		width = max(width, len(row))
+	}
+
+	var padded [][]interface{}
+	for i, row := range data[1:] {
+		if len(row) == width {
+			continue
+		}
+		if padded == nil {
+			padded = append([][]interface{}{}, data...)
+		}
+		full := make([]interface{}, width)
+		copy(full, row)
+		padded[i+1] = full
+	}
+	if padded == nil {
+		return data
+	}
+	return padded
+}
+
+// normalizeHeaders returns a copy of headers with surrounding whitespace
+// trimmed from names and repeated names made unique with a numeric
+// suffix: "Name", "Name", "Name" become "Name", "Name_2", "Name_3".
+// Suffixes skip names already in the row, and blank headers are left as
+// they are.
+func normalizeHeaders(headers []interface{}) []interface{} {
+	out := make([]interface{}, len(headers))
+	taken := make(map[string]bool, len(headers))
+	for i, h := range headers {
+		if s, ok := h.(string); ok {
+			h = strings.TrimSpace(s)
+			taken[h.(string)] = true
+		}
+		out[i] = h
+	}
+
+	seen := make(map[string]bool, len(headers))
+	for i, h := range out {
+		name, ok := h.(string)
+		if !ok || name == "" {
+			continue
+		}
+		if seen[name] {
+			n := 2
+			for taken[name+"_"+strconv.Itoa(n)] {
+				n++
+			}
+			out[i] = name + "_" + strconv.Itoa(n)
+			taken[out[i].(string)] = true
+		}
+		seen[name] = true
+	}
+	return out
+}
+
+// trimToAnchor drops the rows above and the columns left of the anchor
+// from a read that starts at A1.
+func (t *Table) trimToAnchor(data [][]interface{}) [][]interface{} {
+	col, row := t.anchor()
+	if col == 0 && row == 1 {
+		return data
+	}
+
+	if row-1 >= len(data) {
+		return nil
+	}
+	data = data[row-1:]
+
+	trimmed := make([][]interface{}, len(data))
+	for i, r := range data {
+		if col < len(r) {
+			trimmed[i] = r[col:]
+		} else {
+			trimmed[i] = []interface{}{}
+		}
+	}
+	return trimmed
+}
+
+// rowNumber returns the 1-based sheet row of data row i (0-based,
+// excluding the header).
+func (t *Table) rowNumber(i int) int {
+	_, row := t.anchor()
+	return row + 1 + i
+}
+
+// rowRange returns the A1 range of data row i spanning table columns
+// from..to (0-based, relative to the anchor column, inclusive).
+func (t *Table) rowRange(i, from, to int) string {
+	col, _ := t.anchor()
+	r := t.rowNumber(i)
+	return fmt.Sprintf("%s!%s%d:%s%d", t.name, columnIndexToLetter(col+from), r, columnIndexToLetter(col+to), r)
+}
+
+// appendRange returns the range Append targets: the table's header cell.
+func (t *Table) appendRange() string {
+	col, row := t.anchor()
+	return fmt.Sprintf("%s!%s%d", t.name, columnIndexToLetter(col), row)
+}
+
+// naming returns how the table names the columns of untagged fields.
+func (t *Table) naming() fieldNaming {
+	if t.db == nil {
+		return fieldNaming{}
+	}
+	return fieldNaming{fn: t.db.defaultTagFunc, fields: &t.db.namedFields}
+}
+
+// checkHeaders records headers and reports ErrHeaderChanged if they differ
+// from the previously recorded ones.
+func (t *Table) checkHeaders(headers []interface{}) error {
+	if !t.opts.DetectHeaderChanges {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.seenHeaders
+	t.seenHeaders = append([]interface{}{}, headers...)
+	if previous != nil && !sameCells(previous, headers) {
+		return fmt.Errorf("%w: was %v, now %v", ErrHeaderChanged, previous, headers)
+	}
+	return nil
 }
 
 // Query builds a query for the table.
@@ -23,222 +308,1593 @@ func (t *Table) Query() *Query {
 	}
 }
 
-// Insert adds new rows to the table.
+// Count returns the number of data rows in the table, excluding the header.
+func (t *Table) Count(ctx context.Context) (int, error) {
+	return t.Query().Count(ctx)
+}
+
+// Insert adds new rows to the table. records is a slice of structs, or a
+// pointer to one. Large slices are appended in sequential batches of
+// Config.BatchSize rows; if a batch fails, Insert stops and returns the
+// error, leaving earlier batches in place.
 func (t *Table) Insert(ctx context.Context, records interface{}) error {
-	values, err := structSliceToValues(records)
+	values, err := t.naming().structSliceToValues(records)
 	if err != nil {
 		return fmt.Errorf("failed to convert records: %w", err)
 	}
+	return t.appendValues(ctx, values)
+}
+
+// appendValues appends rows after the table's data, growing the sheet
+// first if AutoExpand is on, in batches of the DB's batch size.
+func (t *Table) appendValues(ctx context.Context, values [][]interface{}) error {
+	if t.db.autoExpand && len(values) > 0 {
+		if err := t.ensureCapacity(ctx, values); err != nil {
+			return err
+		}
+	}
+
+	batchSize := t.db.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	range_ := t.appendRange()
+	for start := 0; start < len(values); start += batchSize {
+		end := start + batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		if err := t.db.client.Append(ctx, range_, values[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureCapacity grows the sheet's grid so that values can be appended
+// after the existing data. Used rows are counted from the anchor column.
+func (t *Table) ensureCapacity(ctx context.Context, values [][]interface{}) error {
+	props, err := t.properties(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Read every column: Append places new rows after the last row with
+	// data in any of them, even if the anchor column is blank there.
+	col, row := t.anchor()
+	used, err := t.db.client.Read(ctx, t.name)
+	if err != nil {
+		return fmt.Errorf("failed to read data: %w", err)
+	}
+
+	width := 0
+	for _, v := range values {
+		if len(v) > width {
+			width = len(v)
+		}
+	}
+
+	// Whole-sheet reads start at row 1, so used already includes the
+	// rows above the anchor.
+	extraRows := max(len(used), row) + len(values) - props.RowCount
+	extraCols := col + width - props.ColumnCount
+	if extraRows <= 0 && extraCols <= 0 {
+		return nil
+	}
+
+	if extraRows < 0 {
+		extraRows = 0
+	}
+	if extraCols < 0 {
+		extraCols = 0
+	}
+	if err := t.db.client.ExpandSheet(ctx, t.name, extraRows, extraCols); err != nil {
+		return fmt.Errorf("failed to expand sheet: %w", err)
+	}
+	return nil
+}
+
+// properties returns the sheet's properties from the spreadsheet metadata.
+func (t *Table) properties(ctx context.Context) (*SheetProperties, error) {
+	sheetList, err := t.db.client.ListSheets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sheet properties: %w", err)
+	}
+
+	for i := range sheetList {
+		if sheetList[i].Title == t.name {
+			return &sheetList[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrSheetNotFound, t.name)
+}
 
-	range_ := t.name + "!A1"
-	return t.db.client.Append(ctx, range_, values)
+// Size returns the sheet's grid dimensions from the spreadsheet metadata,
+// without reading any cells. This is the allocated grid, including empty
+// rows and columns past the data, so it is an upper bound on the table's
+// size rather than a count of populated rows.
+func (t *Table) Size(ctx context.Context) (rows int, cols int, err error) {
+	props, err := t.properties(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return props.RowCount, props.ColumnCount, nil
 }
 
 // Update modifies a specific row by its index (0-based, excluding header).
+// Fields are written under the header they map to, wherever it is in the
+// sheet, so reordering the sheet's columns doesn't misplace values; cells
+// of columns the record doesn't map are left as they are. It reads the
+// header row first.
 func (t *Table) Update(ctx context.Context, rowIndex int, record interface{}) error {
 	if rowIndex < 0 {
 		return fmt.Errorf("row index cannot be negative")
 	}
 
-	values, err := structToValues(record)
+	headers, err := t.readHeaders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	values, err := t.headerRow(record, headers)
 	if err != nil {
 		return fmt.Errorf("failed to convert record: %w", err)
 	}
 
-	actualRow := rowIndex + 2
-	colCount := len(values)
-	endCol := columnIndexToLetter(colCount - 1)
-	range_ := fmt.Sprintf("%s!A%d:%s%d", t.name, actualRow, endCol, actualRow)
+	first, last := cellSpan(values)
+	range_ := t.rowRange(rowIndex, first, last)
+	return t.db.client.Write(ctx, range_, [][]interface{}{values[first : last+1]})
+}
 
-	return t.db.client.Write(ctx, range_, [][]interface{}{values})
+// headerRow converts record into a row laid out by headers, or by field
+// order if the sheet has no header row yet.
+func (t *Table) headerRow(record interface{}, headers []interface{}) ([]interface{}, error) {
+	if len(headers) == 0 {
+		return t.naming().structToValues(record)
+	}
+	return t.naming().structToHeaderValues(record, headers, t.db.lenientHeaders)
 }
 
-// UpdateWhere updates all rows matching the filter condition.
-func (t *Table) UpdateWhere(ctx context.Context, column, operator string, value interface{}, record interface{}) error {
-	data, err := t.db.client.Read(ctx, t.name)
+// WriteRows writes each record over the sheet row it is keyed by, in a
+// single batch request. Keys are physical row numbers as the sheet shows
+// them (1-based, counting the header and any rows above it), not the
+// 0-based data indices Update takes, so rows can be put back at known
+// positions:
+//
+//	err := table.WriteRows(ctx, map[int]interface{}{2: alice, 7: bob})
+//
+// Fields are laid out by the header row as for Update. Rows must lie
+// below the header; nothing is written if any doesn't.
+func (t *Table) WriteRows(ctx context.Context, rows map[int]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	top := t.rowNumber(0)
+	for row := range rows {
+		if row < top {
+			return fmt.Errorf("row %d is not below the header row", row)
+		}
+	}
+
+	headers, err := t.readHeaders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	batch := make(map[string][][]interface{}, len(rows))
+	for row, record := range rows {
+		values, err := t.headerRow(record, headers)
+		if err != nil {
+			return fmt.Errorf("failed to convert record for row %d: %w", row, err)
+		}
+		first, last := cellSpan(values)
+		batch[t.rowRange(row-top, first, last)] = [][]interface{}{values[first : last+1]}
+	}
+
+	if err := t.db.client.BatchWrite(ctx, batch); err != nil {
+		return fmt.Errorf("failed to write %d rows: %w", len(rows), err)
+	}
+	return nil
+}
+
+// Upsert updates the rows whose keyColumn matches the record's value for
+// that column, or appends the record as a new row if none do. When several
+// rows share the key, all of them are updated, like UpdateWhere.
+//
+// Fields are matched to columns by header name, so record may be a partial
+// struct: columns it doesn't map are left as they are on update and blank
+// on insert. On an empty sheet, the header row is written from record's
+// fields along with the new row.
+func (t *Table) Upsert(ctx context.Context, keyColumn string, record interface{}) error {
+	key, err := t.naming().columnValue(record, keyColumn)
+	if err != nil {
+		return err
+	}
+
+	data, err := t.readData(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to read data: %w", err)
 	}
 
-	if len(data) < 2 {
-		return nil
+	if len(data) == 0 {
+		headers, err := t.naming().structHeaders(record)
+		if err != nil {
+			return fmt.Errorf("failed to convert record: %w", err)
+		}
+		values, err := t.naming().structToValues(record)
+		if err != nil {
+			return fmt.Errorf("failed to convert record: %w", err)
+		}
+		return t.db.client.Append(ctx, t.appendRange(), [][]interface{}{headers, values})
 	}
 
 	headers := data[0]
-	rows := data[1:]
+	if findColumnLenient(headers, keyColumn, t.db.lenientHeaders) == -1 {
+		return fmt.Errorf("column %q not found", keyColumn)
+	}
 
-	filter := Filter{Column: column, Operator: operator, Value: value}
-	indices := []int{}
-	for i, row := range rows {
+	values, err := t.naming().structToHeaderValues(record, headers, t.db.lenientHeaders)
+	if err != nil {
+		return fmt.Errorf("failed to convert record: %w", err)
+	}
+
+	filter := Filter{Column: keyColumn, Operator: "=", Value: key, lenient: t.db.lenientHeaders}
+	var indices []int
+	for i, row := range data[1:] {
 		if matchesFilter(row, headers, filter) {
 			indices = append(indices, i)
 		}
 	}
 
-	if len(indices) == 0 {
-		return nil
+	if len(indices) > 0 {
+		return t.writeRows(ctx, indices, values)
+	}
+	_, last := cellSpan(values)
+	return t.db.client.Append(ctx, t.appendRange(), [][]interface{}{values[:last+1]})
+}
+
+// cellSpan returns the indices of the first and last non-nil cells in
+// values, or 0, 0 if all are nil.
+func cellSpan(values []interface{}) (first, last int) {
+	first, last = -1, 0
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	if first == -1 {
+		first = 0
+	}
+	return first, last
+}
+
+// UpdateIfUnchanged overwrites the row at rowIndex (0-based, excluding header)
+// with record only if its current contents still match expected. It reports
+// whether the write happened, giving lightweight optimistic concurrency
+// without a version column. Like Update, fields are matched to columns by
+// header name.
+func (t *Table) UpdateIfUnchanged(ctx context.Context, rowIndex int, expected interface{}, record interface{}) (bool, error) {
+	if rowIndex < 0 {
+		return false, fmt.Errorf("row index cannot be negative")
 	}
 
-	values, err := structToValues(record)
+	headers, err := t.readHeaders(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to convert record: %w", err)
+		return false, fmt.Errorf("failed to read headers: %w", err)
 	}
 
-	colCount := len(values)
-	endCol := columnIndexToLetter(colCount - 1)
+	want, err := t.headerRow(expected, headers)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert expected record: %w", err)
+	}
 
-	for _, idx := range indices {
-		actualRow := idx + 2
-		range_ := fmt.Sprintf("%s!A%d:%s%d", t.name, actualRow, endCol, actualRow)
-		if err := t.db.client.Write(ctx, range_, [][]interface{}{values}); err != nil {
-			return fmt.Errorf("failed to update row %d: %w", idx, err)
-		}
+	values, err := t.headerRow(record, headers)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert record: %w", err)
 	}
 
-	return nil
-}
+	current, err := t.readRow(ctx, rowIndex, max(len(want), len(values)))
+	if err != nil {
+		return false, err
+	}
+	if !sameCells(current, want) {
+		return false, nil
+	}
 
-// Delete removes a specific row by its index (0-based, excluding header).
-func (t *Table) Delete(ctx context.Context, rowIndex int) error {
-	if rowIndex < 0 {
-		return fmt.Errorf("row index cannot be negative")
+	first, last := cellSpan(values)
+	if err := t.db.client.Write(ctx, t.rowRange(rowIndex, first, last), [][]interface{}{values[first : last+1]}); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	actualRow := rowIndex + 1
-	return t.db.client.DeleteRows(ctx, t.name, []int{actualRow})
+// readRow reads the first width cells of the data row at rowIndex.
+// Trailing blank cells are omitted, as the API returns them.
+func (t *Table) readRow(ctx context.Context, rowIndex, width int) ([]interface{}, error) {
+	data, err := t.db.client.Read(ctx, t.rowRange(rowIndex, 0, max(width, 1)-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read row %d: %w", rowIndex, err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return data[0], nil
 }
 
-// DeleteWhere removes all rows matching the filter condition.
-func (t *Table) DeleteWhere(ctx context.Context, column, operator string, value interface{}) error {
-	data, err := t.db.client.Read(ctx, t.name)
+// UpdateDiff compares record with the current contents of the row at
+// rowIndex (0-based, excluding header) and writes only the cells that
+// differ, returning how many changed. Unchanged cells are sent as nil,
+// which the Sheets API skips, so formulas in them are left intact. Like
+// Update, fields are matched to columns by header name.
+func (t *Table) UpdateDiff(ctx context.Context, rowIndex int, record interface{}) (int, error) {
+	if rowIndex < 0 {
+		return 0, fmt.Errorf("row index cannot be negative")
+	}
+
+	headers, err := t.readHeaders(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to read data: %w", err)
+		return 0, fmt.Errorf("failed to read headers: %w", err)
 	}
 
-	if len(data) < 2 {
-		return nil
+	values, err := t.headerRow(record, headers)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert record: %w", err)
 	}
 
-	headers := data[0]
-	rows := data[1:]
+	current, err := t.readRow(ctx, rowIndex, len(values))
+	if err != nil {
+		return 0, err
+	}
 
-	filter := Filter{Column: column, Operator: operator, Value: value}
-	indices := []int{}
-	for i, row := range rows {
-		if matchesFilter(row, headers, filter) {
-			indices = append(indices, i+1)
+	first, last, changed := -1, -1, 0
+	sparse := make([]interface{}, len(values))
+	for i, v := range values {
+		var cell interface{}
+		if i < len(current) {
+			cell = current[i]
+		}
+		if v == nil || sameCell(cell, v) {
+			continue
+		}
+		sparse[i] = v
+		changed++
+		if first == -1 {
+			first = i
 		}
+		last = i
 	}
 
-	if len(indices) == 0 {
-		return nil
+	if changed == 0 {
+		return 0, nil
 	}
 
-	sort.Sort(sort.Reverse(sort.IntSlice(indices)))
-
-	return t.db.client.DeleteRows(ctx, t.name, indices)
+	if err := t.db.client.Write(ctx, t.rowRange(rowIndex, first, last), [][]interface{}{sparse[first : last+1]}); err != nil {
+		return 0, err
+	}
+	return changed, nil
 }
 
-func matchesFilter(row []interface{}, headers []interface{}, filter Filter) bool {
-	colIdx := -1
-	for i, h := range headers {
-		if h == filter.Column {
-			colIdx = i
-			break
+// sameCells compares two rows cell by cell with sameCell. Missing trailing
+// cells are treated as blank, matching how Sheets trims empty cells on
+// read. nil cells in b are holes left by structToValues and are not
+// compared.
+func sameCells(a, b []interface{}) bool {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		var x, y interface{}
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			if b[i] == nil {
+				continue
+			}
+			y = b[i]
+		}
+		if !sameCell(x, y) {
+			return false
 		}
 	}
-	if colIdx == -1 || colIdx >= len(row) {
-		return false
+	return true
+}
+
+// sameCell reports whether two cells hold the same value, comparing them
+// as the sheet displays them: numbers written out in full, and booleans
+// ignoring case, since formatted reads return TRUE and FALSE.
+func sameCell(x, y interface{}) bool {
+	a, b := cellText(x), cellText(y)
+	if a == b {
+		return true
 	}
+	_, xBool := x.(bool)
+	_, yBool := y.(bool)
+	return (xBool || yBool) && strings.EqualFold(a, b)
+}
 
-	return matchesOperator(row[colIdx], filter.Operator, filter.Value)
+func cellText(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return formatCell(v)
 }
 
-func columnIndexToLetter(index int) string {
-	if index < 0 {
-		return "A"
+// UpdateWhere updates all rows matching the filter condition. Like Update,
+// it writes each field under the header it maps to. The rows are written
+// in batch requests of Config.WriteBatchSize rows; if one fails, the
+// earlier ones stay written and the error reports how many rows were.
+func (t *Table) UpdateWhere(ctx context.Context, column, operator string, value interface{}, record interface{}) error {
+	headers, indices, err := t.matchingRows(ctx, column, operator, value)
+	if err != nil {
+		return err
 	}
-	result := ""
-	for index >= 0 {
-		result = string(rune('A'+index%26)) + result
-		index = index/26 - 1
+
+	if len(indices) == 0 {
+		return nil
+	}
+	return t.updateRows(ctx, headers, indices, record)
+}
+
+// updateRows writes record, laid out by headers, over the data rows at
+// indices (0-based, excluding the header).
+func (t *Table) updateRows(ctx context.Context, headers []interface{}, indices []int, record interface{}) error {
+	values, err := t.headerRow(record, headers)
+	if err != nil {
+		return fmt.Errorf("failed to convert record: %w", err)
+	}
+	return t.writeRows(ctx, indices, values)
+}
+
+// writeRows writes values over the data rows at indices, in sequential
+// batch requests of Config.WriteBatchSize rows. nil cells are left as they
+// are. If a batch fails, writeRows stops, leaving earlier batches written,
+// and the error says how many rows were updated.
+func (t *Table) writeRows(ctx context.Context, indices []int, values []interface{}) error {
+	batchSize := t.db.writeBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultWriteBatchSize
+	}
+
+	first, last := cellSpan(values)
+	for start := 0; start < len(indices); start += batchSize {
+		end := min(start+batchSize, len(indices))
+		batch := make(map[string][][]interface{}, end-start)
+		for _, idx := range indices[start:end] {
+			batch[t.rowRange(idx, first, last)] = [][]interface{}{values[first : last+1]}
+		}
+		if err := t.db.client.BatchWrite(ctx, batch); err != nil {
+			if start > 0 {
+				return fmt.Errorf("failed to update %d rows after updating %d: %w", len(indices)-start, start, err)
+			}
+			return fmt.Errorf("failed to update %d rows: %w", len(indices), err)
+		}
+	}
+	return nil
+}
+
+// UpdateFields writes only the given columns of a row (0-based index,
+// excluding the header), keyed by header name, leaving its other cells as
+// they are. A nil value clears its cell. It reads the header row first
+// and returns an error, without writing, if a column doesn't exist.
+//
+//	err := table.UpdateFields(ctx, 3, map[string]interface{}{"Status": "shipped"})
+func (t *Table) UpdateFields(ctx context.Context, rowIndex int, fields map[string]interface{}) error {
+	if rowIndex < 0 {
+		return fmt.Errorf("row index cannot be negative")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	headers, err := t.readHeaders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	values, err := t.fieldsRow(headers, fields)
+	if err != nil {
+		return err
+	}
+
+	first, last := cellSpan(values)
+	range_ := t.rowRange(rowIndex, first, last)
+	return t.db.client.Write(ctx, range_, [][]interface{}{values[first : last+1]})
+}
+
+// UpdateFieldsWhere is UpdateFields for every row matching the filter
+// condition, written in batches of Config.WriteBatchSize rows.
+func (t *Table) UpdateFieldsWhere(ctx context.Context, column, operator string, value interface{}, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	headers, indices, err := t.matchingRows(ctx, column, operator, value)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+
+	values, err := t.fieldsRow(headers, fields)
+	if err != nil {
+		return err
+	}
+	return t.writeRows(ctx, indices, values)
+}
+
+// fieldsRow lays out fields, keyed by column name, as a row under headers
+// with nil in the other cells.
+func (t *Table) fieldsRow(headers []interface{}, fields map[string]interface{}) ([]interface{}, error) {
+	values := make([]interface{}, len(headers))
+	for name, value := range fields {
+		col := findColumnLenient(headers, name, t.db.lenientHeaders)
+		if col == -1 {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+		if value == nil {
+			value = ""
+		}
+		values[col] = value
+	}
+	return values, nil
+}
+
+// Delete removes a specific row by its index (0-based, excluding header).
+func (t *Table) Delete(ctx context.Context, rowIndex int) error {
+	if rowIndex < 0 {
+		return fmt.Errorf("row index cannot be negative")
+	}
+
+	// DeleteRows takes 0-based sheet row indices.
+	actualRow := t.rowNumber(rowIndex) - 1
+	return t.db.client.DeleteRows(ctx, t.name, []int{actualRow})
+}
+
+// DeleteWhere removes all rows matching the filter condition.
+func (t *Table) DeleteWhere(ctx context.Context, column, operator string, value interface{}) error {
+	_, matched, err := t.matchingRows(ctx, column, operator, value)
+	if err != nil {
+		return err
+	}
+
+	if len(matched) == 0 {
+		return nil
+	}
+	return t.deleteRows(ctx, matched)
+}
+
+// deleteRows removes the data rows at matched (0-based, excluding the
+// header) in a single request.
+func (t *Table) deleteRows(ctx context.Context, matched []int) error {
+	indices := make([]int, len(matched))
+	for i, row := range matched {
+		indices[i] = t.rowNumber(row) - 1
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(indices)))
+
+	return t.db.client.DeleteRows(ctx, t.name, indices)
+}
+
+// CountWhere returns how many rows DeleteWhere would remove with the same
+// condition, so a destructive call can be confirmed first. The sheet may
+// of course change between the two calls.
+func (t *Table) CountWhere(ctx context.Context, column, operator string, value interface{}) (int, error) {
+	_, matched, err := t.matchingRows(ctx, column, operator, value)
+	return len(matched), err
+}
+
+// Exists reports whether any row matches the condition, stopping at the
+// first match. An empty or header-only sheet has no matching rows.
+func (t *Table) Exists(ctx context.Context, column, operator string, value interface{}) (bool, error) {
+	filter, err := t.condition(column, operator, value)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := t.readData(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	if len(data) < 2 {
+		return false, nil
+	}
+
+	headers := data[0]
+	for _, row := range data[1:] {
+		if matchesFilter(row, headers, filter) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ReadAfterID scans into dest, a pointer to a slice of structs, the rows
+// whose idColumn holds a number greater than afterID, in ascending ID
+// order. It suits consuming an append-only log incrementally: pass the
+// largest ID seen so far to get only the rows added since.
+//
+//	var events []Event
+//	err := table.ReadAfterID(ctx, "ID", lastID, &events)
+//
+// IDs are compared as numbers, so rows whose ID is blank or not a number
+// are never returned. It returns an error if the sheet has no idColumn.
+func (t *Table) ReadAfterID(ctx context.Context, idColumn string, afterID interface{}, dest interface{}) error {
+	return t.Query().
+		Strict().
+		Where(idColumn, ">n", afterID).
+		OrderBy(idColumn, false).
+		Get(ctx, dest)
+}
+
+// matchingRows reads the table and returns its header row and the indices
+// (0-based, excluding the header) of the rows matching the condition.
+func (t *Table) matchingRows(ctx context.Context, column, operator string, value interface{}) ([]interface{}, []int, error) {
+	filter, err := t.condition(column, operator, value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := t.readData(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	if len(data) < 2 {
+		return nil, nil, nil
+	}
+
+	headers := data[0]
+	var matched []int
+	for i, row := range data[1:] {
+		if matchesFilter(row, headers, filter) {
+			matched = append(matched, i)
+		}
+	}
+	return headers, matched, nil
+}
+
+// condition builds the filter for a table-level condition such as
+// DeleteWhere's, compiling a regular expression value.
+func (t *Table) condition(column, operator string, value interface{}) (Filter, error) {
+	filters, err := compilePatterns([]Filter{{Column: column, Operator: operator, Value: value, lenient: t.db.lenientHeaders}}, false)
+	if err != nil {
+		return Filter{}, err
+	}
+	return filters[0], nil
+}
+
+// Truncate clears every data row while keeping the header row. Only the
+// table's columns, as given by its header, are cleared. Cell formatting is
+// kept and the grid keeps its size; rows are emptied, not deleted.
+func (t *Table) Truncate(ctx context.Context) error {
+	headers, err := t.readHeaders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	col, row := t.anchor()
+	range_ := fmt.Sprintf("%s!%s%d:%s", t.name, columnIndexToLetter(col), row+1, columnIndexToLetter(col+len(headers)-1))
+	return t.db.client.Clear(ctx, range_)
+}
+
+// Clear clears the whole table, header row included. For a table at A1
+// this clears the entire sheet; an anchored table clears only its own
+// columns from the header row down.
+func (t *Table) Clear(ctx context.Context) error {
+	col, row := t.anchor()
+	if col == 0 && row == 1 {
+		return t.db.client.Clear(ctx, t.name)
+	}
+
+	headers, err := t.readHeaders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	range_ := fmt.Sprintf("%s!%s%d:%s", t.name, columnIndexToLetter(col), row, columnIndexToLetter(col+len(headers)-1))
+	return t.db.client.Clear(ctx, range_)
+}
+
+// ColumnWithRows returns the values of the named column keyed by their
+// physical sheet row number (1-based, so the first data row is 2 unless
+// the table is anchored lower down).
+// Blank cells are skipped and do not appear in the map.
+func (t *Table) ColumnWithRows(ctx context.Context, name string) (map[int]string, error) {
+	data, err := t.readData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	result := make(map[int]string)
+	if len(data) == 0 {
+		return result, nil
+	}
+
+	colIdx := findColumnLenient(data[0], name, t.db.lenientHeaders)
+	if colIdx == -1 {
+		return nil, fmt.Errorf("column %q not found", name)
+	}
+
+	for i, row := range data[1:] {
+		if colIdx >= len(row) || row[colIdx] == nil {
+			continue
+		}
+		value := fmt.Sprintf("%v", row[colIdx])
+		if value == "" {
+			continue
+		}
+		result[t.rowNumber(i)] = value
+	}
+
+	return result, nil
+}
+
+// Headers returns the column names in the table's header row, reading
+// only that row. Blank header cells are returned as empty strings, so each
+// name's index is its column's position from the anchor column. A sheet
+// with nothing in its header row returns an empty slice.
+func (t *Table) Headers(ctx context.Context) ([]string, error) {
+	headers, err := t.readHeaders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	names := make([]string, len(headers))
+	for i, h := range headers {
+		if h != nil {
+			names[i] = formatCell(h)
+		}
+	}
+	return names, nil
+}
+
+// Columns returns the named columns as parallel slices of cell values,
+// aligned by data row (index 0 is the first row after the header). Missing
+// cells are returned as empty strings, so every slice has the same length.
+func (t *Table) Columns(ctx context.Context, names ...string) (map[string][]string, error) {
+	data, err := t.readData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	var headers []interface{}
+	var rows [][]interface{}
+	if len(data) > 0 {
+		headers = data[0]
+		rows = data[1:]
+	}
+
+	result := make(map[string][]string, len(names))
+	for _, name := range names {
+		colIdx := findColumnLenient(headers, name, t.db.lenientHeaders)
+		if colIdx == -1 {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			if colIdx < len(row) {
+				values[i] = cellText(row[colIdx])
+			}
+		}
+		result[name] = values
+	}
+
+	return result, nil
+}
+
+func findColumn(headers []interface{}, name string) int {
+	for i, h := range headers {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// findColumnLenient is findColumn that, when lenient is set and there is
+// no exact match, also matches headers that differ from name only in
+// surrounding whitespace and case (Config.LenientHeaders).
+func findColumnLenient(headers []interface{}, name string, lenient bool) int {
+	if idx := findColumn(headers, name); idx != -1 || !lenient {
+		return idx
+	}
+
+	name = strings.TrimSpace(name)
+	for i, h := range headers {
+		if s, ok := h.(string); ok && strings.EqualFold(strings.TrimSpace(s), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveCell returns the cell for column in row. A column of the form
+// "Meta->role" or "Meta->user.role" parses the Meta cell as a JSON object
+// and returns the value at the dotted key path.
+func resolveCell(row []interface{}, headers []interface{}, column string, lenient bool) (interface{}, bool) {
+	colIdx := findColumnLenient(headers, column, lenient)
+	path := ""
+	if colIdx == -1 {
+		name, rest, ok := strings.Cut(column, "->")
+		if !ok {
+			return nil, false
+		}
+		colIdx = findColumnLenient(headers, name, lenient)
+		path = rest
+	}
+	if colIdx == -1 || colIdx >= len(row) || row[colIdx] == nil {
+		return nil, false
+	}
+
+	if path == "" {
+		return row[colIdx], true
+	}
+	return jsonPathValue(row[colIdx], path)
+}
+
+func jsonPathValue(cell interface{}, path string) (interface{}, bool) {
+	var current interface{}
+	if err := json.Unmarshal([]byte(fmt.Sprintf("%v", cell)), &current); err != nil {
+		return nil, false
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func matchesFilter(row []interface{}, headers []interface{}, filter Filter) bool {
+	cell, ok := resolveCell(row, headers, filter.Column, filter.lenient)
+	if !ok {
+		// A blank or missing cell only satisfies the emptiness operators,
+		// and only in a column the table actually has.
+		empty, isEmptyOp := emptyOperator(filter.Operator)
+		return isEmptyOp && empty && hasColumn(headers, filter.Column, filter.lenient)
+	}
+
+	if filter.parse != nil {
+		if matched, handled := matchesParsed(cell, filter); handled {
+			return matched
+		}
+	}
+	return matchesOperatorFold(cell, filter.Operator, filter.Value, filter.foldCase)
+}
+
+// matchesParsed evaluates a comparison filter on a column with a
+// ParseColumn parser, comparing the parsed numbers. handled is false for
+// the other operators, which see the cell as it is.
+func matchesParsed(cell interface{}, filter Filter) (matched, handled bool) {
+	op := strings.TrimSuffix(filter.Operator, "n")
+	if op == "==" {
+		op = "="
+	}
+	switch op {
+	case "=", "!=", ">", ">=", "<", "<=":
+	default:
+		return false, false
+	}
+
+	a, aOK := parseNumber(cell, filter.parse)
+	b, bOK := parseNumber(filter.Value, filter.parse)
+	if !aOK || !bOK {
+		return false, true
+	}
+	return matchesComparison(op, compareFloats(a, b)), true
+}
+
+// parseNumber reads v with a ParseColumn parser, falling back to a plain
+// number.
+func parseNumber(v interface{}, parse func(string) (float64, error)) (float64, bool) {
+	s := strings.TrimSpace(formatCell(v))
+	f, err := parse(s)
+	if err != nil {
+		if f, err = strconv.ParseFloat(s, 64); err != nil {
+			return 0, false
+		}
+	}
+	return f, !math.IsNaN(f)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func columnIndexToLetter(index int) string {
+	if index < 0 {
+		return "A"
+	}
+	result := ""
+	for index >= 0 {
+		result = string(rune('A'+index%26)) + result
+		index = index/26 - 1
+	}
+	return result
+}
+
+// Query provides a fluent interface for building queries.
+type Query struct {
+	table      *Table
+	filters    []Filter
+	limit      int
+	percent    float64
+	orderBy    string
+	descending bool
+	nullsLast  bool
+	selected   []string
+	strictScan bool
+	strict     bool
+	byPosition bool
+	foldCase   bool
+	timeout    time.Duration
+	mappers    []func(record interface{}) interface{}
+
+	// parsers holds the ParseColumn parsers, by column name.
+	parsers map[string]func(string) (float64, error)
+
+	// union holds the tables read after table in a Union query.
+	union []*Table
+}
+
+// Filter represents a WHERE condition.
+type Filter struct {
+	Column   string
+	Operator string
+	Value    interface{}
+
+	// or joins this filter to the previous one with OR instead of AND.
+	or bool
+
+	// foldCase makes equality and list operators ignore case; it is set
+	// from Query.CaseInsensitive when the query runs.
+	foldCase bool
+
+	// lenient matches Column to headers ignoring whitespace and case; it
+	// is set from Config.LenientHeaders when the filter runs.
+	lenient bool
+
+	// parse is the column's ParseColumn parser, set when the filter runs.
+	parse func(string) (float64, error)
+}
+
+// Where adds a filter condition.
+func (q *Query) Where(column, operator string, value interface{}) *Query {
+	q.filters = append(q.filters, Filter{
+		Column:   column,
+		Operator: operator,
+		Value:    value,
+	})
+	return q
+}
+
+// OrWhere adds a filter condition OR-ed with the preceding one. Consecutive
+// OR-ed conditions form a group, and groups are AND-ed together, so
+// Where(A).OrWhere(B).Where(C) means (A OR B) AND C.
+func (q *Query) OrWhere(column, operator string, value interface{}) *Query {
+	q.filters = append(q.filters, Filter{
+		Column:   column,
+		Operator: operator,
+		Value:    value,
+		or:       true,
+	})
+	return q
+}
+
+// WhereExistsIn adds a filter keeping rows whose column value appears in
+// otherColumn of the sheet otherTable, like an EXISTS subquery: "users who
+// have orders" is
+//
+//	db.Table("Users").Query().WhereExistsIn("ID", "Orders", "UserID")
+//
+// The other sheet is read once each time the query runs, and its values
+// are compared as text, ignoring case with CaseInsensitive. Blank cells
+// match nothing. Running the query returns an error if the other sheet
+// has no otherColumn.
+func (q *Query) WhereExistsIn(column, otherTable, otherColumn string) *Query {
+	return q.Where(column, opExistsIn, subquery{table: otherTable, column: otherColumn})
+}
+
+// WhereNotExistsIn is the opposite of WhereExistsIn, an anti-join: it
+// keeps rows whose column value does not appear in otherColumn of the
+// sheet otherTable, such as products that were never ordered:
+//
+//	db.Table("Products").Query().WhereNotExistsIn("SKU", "Orders", "SKU")
+//
+// Rows with a blank cell in column match neither filter.
+func (q *Query) WhereNotExistsIn(column, otherTable, otherColumn string) *Query {
+	return q.Where(column, opNotExistsIn, subquery{table: otherTable, column: otherColumn})
+}
+
+// WhereDateBetween adds a filter matching rows whose column holds a date
+// between from and to, inclusive. Cells are parsed with the layouts in
+// dateLayouts, in from's location unless they carry a zone; cells that
+// don't parse as a date never match. Note that a to bound at midnight
+// excludes later times on that day.
+func (q *Query) WhereDateBetween(column string, from, to time.Time) *Query {
+	return q.Where(column, opDateBetween, dateRange{from: from, to: to})
+}
+
+// Limit sets the maximum number of results.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// LimitPercent keeps the first p percent of the matching rows, rounded
+// up, e.g. 3 of 11 rows for p = 25. p above 100 keeps every row and p of
+// zero or less disables the percentage limit. Combined with Limit, the
+// smaller of the two applies.
+func (q *Query) LimitPercent(p float64) *Query {
+	q.percent = min(p, 100)
+	return q
+}
+
+// OrderBy sets the sort column and direction.
+func (q *Query) OrderBy(column string, descending bool) *Query {
+	q.orderBy = column
+	q.descending = descending
+	return q
+}
+
+// NullsLast controls whether blank or missing cells in the order-by column
+// sort after all other values regardless of direction, like SQL's NULLS LAST.
+func (q *Query) NullsLast(enabled bool) *Query {
+	q.nullsLast = enabled
+	return q
+}
+
+// Select restricts the columns read from the sheet and scanned into the
+// destination; fields mapped to other columns are left at their zero
+// value. Columns used by Where and OrderBy are read but not scanned, and
+// names that aren't in the header are ignored. On wide sheets this fetches
+// only the needed column ranges instead of every column.
+func (q *Query) Select(columns ...string) *Query {
+	q.selected = append(q.selected, columns...)
+	return q
+}
+
+// CaseInsensitive makes "=", "!=", "in", "not in", "regex" and
+// WhereExistsIn ignore case, as "contains" and "like" always do. It is off
+// by default.
+func (q *Query) CaseInsensitive(enabled bool) *Query {
+	q.foldCase = enabled
+	return q
+}
+
+// ParseColumn sets how the comparison operators (=, !=, >, >=, <, <= and
+// their "n" forms) and OrderBy read column, for cells in a format of their
+// own such as "1.2K" or "2h15m". parse turns a cell's text into a number;
+// the Where value is parsed the same way unless it is already a number.
+// Cells parse cannot read, or that aren't plain numbers either, match no
+// comparison and sort as text. A nil parse removes the column's parser.
+//
+//	q.ParseColumn("Followers", parseShorthand).Where("Followers", ">", "1M")
+func (q *Query) ParseColumn(column string, parse func(string) (float64, error)) *Query {
+	if parse == nil {
+		delete(q.parsers, column)
+		return q
+	}
+	if q.parsers == nil {
+		q.parsers = make(map[string]func(string) (float64, error))
+	}
+	q.parsers[column] = parse
+	return q
+}
+
+// StrictScan makes Get report cells that can't be converted to their
+// field's type. Every row is still scanned; all failures are returned
+// together as ScanErrors so they can be fixed in one pass.
+func (q *Query) StrictScan() *Query {
+	q.strictScan = true
+	return q
+}
+
+// Strict makes the query fail with an error naming the unknown columns
+// if a Where or OrderBy column isn't in the header row, instead of
+// silently matching nothing (or not sorting). Off by default.
+func (q *Query) Strict() *Query {
+	q.strict = true
+	return q
+}
+
+// checkColumns returns an error listing the filter and order-by columns
+// missing from headers, if the query is strict.
+func (q *Query) checkColumns(headers []interface{}) error {
+	if !q.strict {
+		return nil
+	}
+
+	lenient := q.lenientHeaders()
+	var unknown []string
+	seen := make(map[string]bool)
+	check := func(column string) {
+		if hasColumn(headers, column, lenient) {
+			return
+		}
+		if !seen[column] {
+			seen[column] = true
+			unknown = append(unknown, strconv.Quote(column))
+		}
+	}
+
+	for _, f := range q.filters {
+		check(f.Column)
+	}
+	if q.orderBy != "" {
+		check(q.orderBy)
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("query references unknown columns: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// ByPosition maps struct fields to columns by position instead of by
+// header name: the first field reads column A (or the anchor column), the
+// second the next one, and so on, the same order Insert writes them in.
+// Fields with a col: tag still read that column. Use it for sheets whose
+// header row has no meaningful names; the first row is still treated as
+// the header and skipped. Where and OrderBy keep using header names.
+func (q *Query) ByPosition() *Query {
+	q.byPosition = true
+	return q
+}
+
+// Map adds a transformation applied to each record after it is scanned.
+// fn receives the record as the destination's element type (e.g. User,
+// not *User) and must return a value of that same type; any other return
+// makes Get or First fail. Several Map calls are applied in order.
+func (q *Query) Map(fn func(record interface{}) interface{}) *Query {
+	q.mappers = append(q.mappers, fn)
+	return q
+}
+
+// applyMappers runs the Map functions on the scanned record in elem.
+func (q *Query) applyMappers(elem reflect.Value) error {
+	for _, fn := range q.mappers {
+		result := fn(elem.Interface())
+		out := reflect.ValueOf(result)
+		if !out.IsValid() || !out.Type().AssignableTo(elem.Type()) {
+			return fmt.Errorf("map function returned %T, want %s", result, elem.Type())
+		}
+		elem.Set(out)
+	}
+	return nil
+}
+
+// Timeout bounds how long the query may take when it runs, without the
+// caller having to derive a context with context.WithTimeout.
+func (q *Query) Timeout(d time.Duration) *Query {
+	q.timeout = d
+	return q
+}
+
+// Reset clears the query's filters, limit, ordering, selected columns,
+// mappers and other options, so it behaves like a fresh query on the same
+// table (or union) and can be reused without allocating a new one. The
+// old filters are dropped rather than cleared, as an iterator or copy
+// made from q may still use them.
+func (q *Query) Reset() *Query {
+	*q = Query{table: q.table, union: q.union}
+	return q
+}
+
+// lenientHeaders reports whether the query's DB matches column names
+// leniently.
+func (q *Query) lenientHeaders() bool {
+	return q.table != nil && q.table.db != nil && q.table.db.lenientHeaders
+}
+
+// withTimeout derives the context a query runs under.
+func (q *Query) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if q.timeout > 0 {
+		return context.WithTimeout(ctx, q.timeout)
+	}
+	return ctx, func() {}
+}
+
+// Get executes the query and scans results into the provided slice.
+func (q *Query) Get(ctx context.Context, dest interface{}) error {
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	headers, rows, err := q.rows(ctx)
+	if err != nil {
+		return err
+	}
+	if headers == nil {
+		return nil
+	}
+
+	return q.scanResults(q.applyLimit(rows), headers, dest)
+}
+
+// GetPage is Get for one page of results: it scans at most Limit rows into
+// dest and reports whether more matching rows follow, so a paginated view
+// knows whether to offer a next page without a separate Count. Without a
+// limit, every row fits and hasMore is false.
+func (q *Query) GetPage(ctx context.Context, dest interface{}) (hasMore bool, err error) {
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	headers, rows, err := q.rows(ctx)
+	if err != nil {
+		return false, err
+	}
+	if headers == nil {
+		return false, nil
+	}
+
+	page := q.applyLimit(rows)
+	if err := q.scanResults(page, headers, dest); err != nil {
+		return false, err
+	}
+	return len(page) < len(rows), nil
+}
+
+// scanResults scans rows into the slice dest points to and applies the
+// query's mappers.
+func (q *Query) scanResults(rows [][]interface{}, headers []interface{}, dest interface{}) error {
+	if err := q.scanner().scanIntoSlice(rows, headers, dest); err != nil {
+		return err
+	}
+
+	if len(q.mappers) > 0 {
+		slice := reflect.ValueOf(dest).Elem()
+		for i := 0; i < slice.Len(); i++ {
+			if err := q.applyMappers(slice.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// First scans the first matching row into dest, which must be a pointer to
+// a struct. It returns ErrNoRows when no row matches.
+func (q *Query) First(ctx context.Context, dest interface{}) error {
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a pointer to a struct")
+	}
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	headers, rows, err := q.rows(ctx)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return ErrNoRows
+	}
+
+	if err := q.scanner().scanRow(rows[0], headers, destVal); err != nil {
+		return err
+	}
+	return q.applyMappers(destVal.Elem())
+}
+
+// scanner returns the scanner for the query's results.
+func (q *Query) scanner() scanner {
+	s := scanner{strict: q.strictScan, byPosition: q.byPosition, lenient: q.lenientHeaders()}
+	if q.table != nil {
+		s.naming = q.table.naming()
+	}
+	if len(q.selected) > 0 {
+		s.columns = make(map[string]bool, len(q.selected))
+		for _, name := range q.selected {
+			s.columns[name] = true
+		}
+	}
+	return s
+}
+
+// rows reads the table and returns its headers and the rows matching the
+// query's filters, sorted but not limited. headers is nil when the sheet
+// has no data rows.
+func (q *Query) rows(ctx context.Context) ([]interface{}, [][]interface{}, error) {
+	data, err := q.read(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	if len(data) > 0 && len(q.union) == 0 {
+		if err := q.table.checkHeaders(data[0]); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(data) > 0 {
+		if err := q.checkColumns(data[0]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rq, err := q.resolveFilters(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(data) < 2 {
+		return nil, nil, nil
+	}
+
+	headers := data[0]
+	filtered := rq.applyFilters(data[1:], headers)
+
+	if q.orderBy != "" {
+		filtered = q.applySort(filtered, headers)
+	}
+
+	return headers, filtered, nil
+}
+
+// Count returns the number of rows matching the query's filters.
+// Limit is ignored.
+func (q *Query) Count(ctx context.Context) (int, error) {
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	data, err := q.read(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	if len(data) > 0 && len(q.union) == 0 {
+		if err := q.table.checkHeaders(data[0]); err != nil {
+			return 0, err
+		}
+	}
+	if len(data) > 0 {
+		if err := q.checkColumns(data[0]); err != nil {
+			return 0, err
+		}
+	}
+
+	rq, err := q.resolveFilters(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) < 2 {
+		return 0, nil
+	}
+
+	headers := data[0]
+	rq = rq.resolveNow(time.Now())
+	count := 0
+	for i, row := range data[1:] {
+		if rq.matchesFiltersAt(i, row, headers) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Distinct returns the distinct values of column among the rows matching
+// the query's filters, as strings in the order first seen (after
+// OrderBy, if set). Blank cells are skipped and Limit is ignored. It
+// returns an error if the sheet has no such column.
+func (q *Query) Distinct(ctx context.Context, column string) ([]string, error) {
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	headers, rows, err := q.rows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if headers == nil {
+		return []string{}, nil
+	}
+
+	colIdx := findColumnLenient(headers, column, q.lenientHeaders())
+	if colIdx == -1 {
+		return nil, fmt.Errorf("column %q not found", column)
+	}
+
+	values := []string{}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		if colIdx >= len(row) || row[colIdx] == nil {
+			continue
+		}
+		value := formatCell(row[colIdx])
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// maxProjectedRanges bounds how many separate column ranges a projected
+// read fetches before it is cheaper to read the whole sheet.
+const maxProjectedRanges = 5
+
+func (q *Query) read(ctx context.Context) ([][]interface{}, error) {
+	if len(q.union) > 0 {
+		return q.readUnion(ctx)
+	}
+	if len(q.selected) == 0 || q.table.snapshot != nil {
+		return q.table.queryData(ctx)
 	}
-	return result
-}
 
-// Query provides a fluent interface for building queries.
-type Query struct {
-	table      *Table
-	filters    []Filter
-	limit      int
-	orderBy    string
-	descending bool
+	needed := append([]string{}, q.selected...)
+	for _, f := range q.filters {
+		name, _, _ := strings.Cut(f.Column, "->")
+		needed = append(needed, f.Column, name)
+	}
+	if q.orderBy != "" {
+		needed = append(needed, q.orderBy)
+	}
+	return q.table.readColumns(ctx, needed)
 }
 
-// Filter represents a WHERE condition.
-type Filter struct {
-	Column   string
-	Operator string
-	Value    interface{}
+// readHeaders reads just the table's header row.
+func (t *Table) readHeaders(ctx context.Context) ([]interface{}, error) {
+	data, err := t.db.client.Read(ctx, t.headerRange())
+	if err != nil {
+		return nil, err
+	}
+	return t.headerCells(data), nil
 }
 
-// Where adds a filter condition.
-func (q *Query) Where(column, operator string, value interface{}) *Query {
-	q.filters = append(q.filters, Filter{
-		Column:   column,
-		Operator: operator,
-		Value:    value,
-	})
-	return q
+// headerRange returns the A1 range of the table's header row.
+func (t *Table) headerRange() string {
+	_, row := t.anchor()
+	if last, ok := t.maxScanColumn(); ok {
+		return fmt.Sprintf("%s!A%d:%s%d", t.name, row, last, row)
+	}
+	return fmt.Sprintf("%s!%d:%d", t.name, row, row)
 }
 
-// Limit sets the maximum number of results.
-func (q *Query) Limit(n int) *Query {
-	q.limit = n
-	return q
+// headerCells returns the header row from a read of headerRange, starting
+// at the anchor column.
+func (t *Table) headerCells(data [][]interface{}) []interface{} {
+	col, _ := t.anchor()
+	if len(data) == 0 || col >= len(data[0]) {
+		return nil
+	}
+	if t.db.normalizeHeaders {
+		return normalizeHeaders(data[0][col:])
+	}
+	return data[0][col:]
 }
 
-// OrderBy sets the sort column and direction.
-func (q *Query) OrderBy(column string, descending bool) *Query {
-	q.orderBy = column
-	q.descending = descending
-	return q
-}
+// readColumns reads the header row, then fetches only the ranges covering
+// the named columns in a single batch and reassembles full-width rows with
+// nil in the columns that weren't read. It falls back to reading the whole
+// sheet when the columns are scattered over too many ranges.
+func (t *Table) readColumns(ctx context.Context, columns []string) ([][]interface{}, error) {
+	client := t.db.client
 
-// Get executes the query and scans results into the provided slice.
-func (q *Query) Get(ctx context.Context, dest interface{}) error {
-	range_ := q.table.name
-	data, err := q.table.db.client.Read(ctx, range_)
+	anchorCol, anchorRow := t.anchor()
+	headers, err := t.readHeaders(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to read data: %w", err)
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, nil
 	}
 
-	if len(data) < 2 {
-		return nil
+	var indices []int
+	for _, name := range columns {
+		if idx := findColumnLenient(headers, name, t.db.lenientHeaders); idx != -1 {
+			indices = append(indices, idx)
+		}
+	}
+	runs := columnRuns(indices)
+	if len(runs) == 0 || len(runs) > maxProjectedRanges {
+		return t.readData(ctx)
 	}
 
-	headers := data[0]
-	rows := data[1:]
+	ranges := make([]string, len(runs))
+	for i, run := range runs {
+		from, to := columnIndexToLetter(anchorCol+run[0]), columnIndexToLetter(anchorCol+run[1])
+		if anchorRow == 1 {
+			ranges[i] = fmt.Sprintf("%s!%s:%s", t.name, from, to)
+		} else {
+			ranges[i] = fmt.Sprintf("%s!%s%d:%s", t.name, from, anchorRow, to)
+		}
+	}
+
+	results, err := client.BatchRead(ctx, ranges)
+	if err != nil {
+		return nil, err
+	}
 
-	filtered := q.applyFilters(rows, headers)
+	rowCount := 0
+	for _, r := range ranges {
+		if len(results[r]) > rowCount {
+			rowCount = len(results[r])
+		}
+	}
+	if rowCount == 0 {
+		// The sheet was cleared after the header read.
+		return [][]interface{}{headers}, nil
+	}
 
-	if q.orderBy != "" {
-		filtered = q.applySort(filtered, headers)
+	data := make([][]interface{}, rowCount)
+	data[0] = headers
+	for i := 1; i < rowCount; i++ {
+		data[i] = make([]interface{}, len(headers))
+	}
+	for ri, run := range runs {
+		values := results[ranges[ri]]
+		for i := 1; i < len(values); i++ {
+			for k, cell := range values[i] {
+				if run[0]+k < len(headers) {
+					data[i][run[0]+k] = cell
+				}
+			}
+		}
 	}
+	return data, nil
+}
 
-	filtered = q.applyLimit(filtered)
+// columnRuns groups column indices into sorted, contiguous [start, end] runs.
+func columnRuns(indices []int) [][2]int {
+	if len(indices) == 0 {
+		return nil
+	}
 
-	return scanIntoSlice(filtered, headers, dest)
+	sorted := append([]int{}, indices...)
+	sort.Ints(sorted)
+
+	runs := [][2]int{{sorted[0], sorted[0]}}
+	for _, idx := range sorted[1:] {
+		last := &runs[len(runs)-1]
+		switch {
+		case idx <= last[1]:
+		case idx == last[1]+1:
+			last[1] = idx
+		default:
+			runs = append(runs, [2]int{idx, idx})
+		}
+	}
+	return runs
 }
 
 func (q *Query) applyFilters(rows [][]interface{}, headers []interface{}) [][]interface{} {
 	if len(q.filters) == 0 {
 		return rows
 	}
+	q = q.resolveNow(time.Now())
 
 	var result [][]interface{}
-	for _, row := range rows {
-		if q.matchesFilters(row, headers) {
+	for i, row := range rows {
+		if q.matchesFiltersAt(i, row, headers) {
 			result = append(result, row)
 		}
 	}
@@ -246,34 +1902,68 @@ func (q *Query) applyFilters(rows [][]interface{}, headers []interface{}) [][]in
 }
 
 func (q *Query) matchesFilters(row []interface{}, headers []interface{}) bool {
-	for _, f := range q.filters {
-		colIdx := -1
-		for i, h := range headers {
-			if h == f.Column {
-				colIdx = i
-				break
+	return q.matchesFiltersAt(-1, row, headers)
+}
+
+// matchesFiltersAt evaluates the filters against the data row at rowIndex,
+// reporting each evaluated filter to Config.FilterTrace if it is set.
+func (q *Query) matchesFiltersAt(rowIndex int, row []interface{}, headers []interface{}) bool {
+	if len(q.filters) == 0 {
+		return true
+	}
+
+	var trace func(int, Filter, bool)
+	if q.table != nil && q.table.db != nil {
+		trace = q.table.db.filterTrace
+	}
+
+	groupMatched := false
+	for i, f := range q.filters {
+		if i > 0 && !f.or {
+			if !groupMatched {
+				return false
 			}
+			groupMatched = false
 		}
-		if colIdx == -1 || colIdx >= len(row) {
-			return false
+		if groupMatched {
+			continue
 		}
-
-		if !matchesOperator(row[colIdx], f.Operator, f.Value) {
-			return false
+		f.foldCase = q.foldCase
+		f.lenient = q.lenientHeaders()
+		f.parse = q.parsers[f.Column]
+		groupMatched = matchesFilter(row, headers, f)
+		if trace != nil {
+			trace(rowIndex, f, groupMatched)
 		}
 	}
-	return true
+	return groupMatched
 }
 
 func matchesOperator(cell interface{}, op string, value interface{}) bool {
-	cellStr := fmt.Sprintf("%v", cell)
-	valueStr := fmt.Sprintf("%v", value)
+	return matchesOperatorFold(cell, op, value, false)
+}
+
+// matchesOperatorFold is matchesOperator with optional case folding for
+// the equality and list operators.
+func matchesOperatorFold(cell interface{}, op string, value interface{}, foldCase bool) bool {
+	cellStr := formatCell(cell)
+
+	if _, ok := value.(nowPlaceholder); ok {
+		value = time.Now()
+	}
+	if t, ok := value.(time.Time); ok {
+		if matched, handled := matchesTime(cellStr, op, t); handled {
+			return matched
+		}
+	}
+
+	valueStr := formatCell(value)
 
 	switch op {
 	case "=", "==":
-		return cellStr == valueStr
+		return equalText(cellStr, valueStr, foldCase)
 	case "!=":
-		return cellStr != valueStr
+		return !equalText(cellStr, valueStr, foldCase)
 	case ">":
 		return compareValues(cell, value) > 0
 	case ">=":
@@ -282,192 +1972,432 @@ func matchesOperator(cell interface{}, op string, value interface{}) bool {
 		return compareValues(cell, value) < 0
 	case "<=":
 		return compareValues(cell, value) <= 0
+	case "=n", ">n", ">=n", "<n", "<=n", "!=n":
+		c, ok := compareNumbers(cellStr, valueStr)
+		return ok && matchesComparison(strings.TrimSuffix(op, "n"), c)
 	case "contains", "like":
 		return strings.Contains(strings.ToLower(cellStr), strings.ToLower(valueStr))
+	case "in":
+		found, ok := inList(cellStr, value, foldCase)
+		return ok && found
+	case "not in":
+		found, ok := inList(cellStr, value, foldCase)
+		return ok && !found
+	case "regex", "matches":
+		re, ok := value.(*regexp.Regexp)
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(valueStr); err != nil {
+				return false
+			}
+		}
+		return re.MatchString(cellStr)
+	case "empty", "isnull", "notempty", "notnull":
+		empty, _ := emptyOperator(op)
+		return (strings.TrimSpace(cellStr) == "") == empty
+	case opExistsIn, opNotExistsIn:
+		set, ok := value.(valueSet)
+		if !ok {
+			return false
+		}
+		if foldCase {
+			cellStr = strings.ToLower(cellStr)
+		}
+		return set[cellStr] == (op == opExistsIn)
+	case opDateBetween:
+		r, ok := value.(dateRange)
+		if !ok {
+			return false
+		}
+		t, ok := parseDate(cellStr, r.from.Location())
+		return ok && !t.Before(r.from) && !t.After(r.to)
 	default:
 		return false
 	}
 }
 
-func compareValues(a, b interface{}) int {
-	aStr := fmt.Sprintf("%v", a)
-	bStr := fmt.Sprintf("%v", b)
-
-	// Try numeric comparison
-	aNum, aErr := strconv.ParseFloat(aStr, 64)
-	bNum, bErr := strconv.ParseFloat(bStr, 64)
+// inList reports whether cellStr equals any element of the slice list,
+// comparing formatted values like "=". ok is false if list isn't a slice.
+func inList(cellStr string, list interface{}, foldCase bool) (found bool, ok bool) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false, false
+	}
 
-	if aErr == nil && bErr == nil {
-		if aNum < bNum {
-			return -1
-		}
-		if aNum > bNum {
-			return 1
+	for i := 0; i < v.Len(); i++ {
+		if equalText(cellStr, formatCell(v.Index(i).Interface()), foldCase) {
+			return true, true
 		}
-		return 0
 	}
+	return false, true
+}
 
-	if aStr < bStr {
-		return -1
+// opExistsIn and opNotExistsIn are the operators behind WhereExistsIn and
+// WhereNotExistsIn. Their value is a subquery, which resolveSubqueries
+// replaces with the valueSet it reads.
+const (
+	opExistsIn    = "exists in"
+	opNotExistsIn = "not exists in"
+)
+
+// subquery names the column of another sheet a filter looks values up in.
+type subquery struct {
+	table, column string
+}
+
+// valueSet holds the distinct texts of a subquery's column, lowercased
+// when the query ignores case.
+type valueSet map[string]bool
+
+// resolveFilters returns q with its filter values made ready to match
+// rows: subqueries read and regular expressions compiled.
+func (q *Query) resolveFilters(ctx context.Context) (*Query, error) {
+	rq, err := q.resolveSubqueries(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if aStr > bStr {
-		return 1
+
+	filters, err := compilePatterns(rq.filters, rq.foldCase)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	if rq == q {
+		copied := *q
+		rq = &copied
+	}
+	rq.filters = filters
+	return rq, nil
 }
 
-func (q *Query) applySort(rows [][]interface{}, headers []interface{}) [][]interface{} {
-	return rows
-}
+// compilePatterns returns a copy of filters with the value of each
+// "regex" and "matches" filter compiled, compiling each distinct pattern
+// once. With foldCase, patterns ignore case. Values that are already a
+// *regexp.Regexp are kept as they are.
+func compilePatterns(filters []Filter, foldCase bool) ([]Filter, error) {
+	compiled := make(map[string]*regexp.Regexp)
+	out := append([]Filter(nil), filters...)
+	for i, f := range out {
+		if !isRegexOperator(f.Operator) {
+			continue
+		}
+		if _, ok := f.Value.(*regexp.Regexp); ok {
+			continue
+		}
 
-func (q *Query) applyLimit(rows [][]interface{}) [][]interface{} {
-	if q.limit > 0 && q.limit < len(rows) {
-		return rows[:q.limit]
+		pattern := fmt.Sprintf("%v", f.Value)
+		if foldCase {
+			pattern = "(?i)" + pattern
+		}
+		re, ok := compiled[pattern]
+		if !ok {
+			var err error
+			re, err = regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern for column %q: %w", f.Column, err)
+			}
+			compiled[pattern] = re
+		}
+		out[i].Value = re
 	}
-	return rows
+	return out, nil
 }
 
-func structSliceToValues(records interface{}) ([][]interface{}, error) {
-	v := reflect.ValueOf(records)
-	if v.Kind() != reflect.Slice {
-		return nil, fmt.Errorf("records must be a slice")
-	}
+func isRegexOperator(op string) bool {
+	return op == "regex" || op == "matches"
+}
 
-	var result [][]interface{}
-	for i := 0; i < v.Len(); i++ {
-		elem := v.Index(i)
-		row, err := structToValues(elem.Interface())
+// resolveSubqueries returns q with the value of every WhereExistsIn and
+// WhereNotExistsIn filter replaced by the set of values in the column it
+// refers to, reading each other sheet once.
+func (q *Query) resolveSubqueries(ctx context.Context) (*Query, error) {
+	var filters []Filter
+	for i, f := range q.filters {
+		sub, ok := f.Value.(subquery)
+		if !ok {
+			continue
+		}
+		if filters == nil {
+			filters = append([]Filter{}, q.filters...)
+		}
+
+		columns, err := q.table.db.Table(sub.table).Columns(ctx, sub.column)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to read %s for subquery: %w", sub.table, err)
+		}
+		set := make(valueSet)
+		for _, v := range columns[sub.column] {
+			if q.foldCase {
+				v = strings.ToLower(v)
+			}
+			if v != "" {
+				set[v] = true
+			}
 		}
-		result = append(result, row)
+		filters[i].Value = set
 	}
-	return result, nil
+	if filters == nil {
+		return q, nil
+	}
+
+	rq := *q
+	rq.filters = filters
+	return &rq, nil
 }
 
-func structToValues(record interface{}) ([]interface{}, error) {
-	v := reflect.ValueOf(record)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
-	}
-	if v.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("record must be a struct")
+// opDateBetween is the operator behind WhereDateBetween; its value is a
+// dateRange.
+const opDateBetween = "date between"
+
+type dateRange struct {
+	from, to time.Time
+}
+
+// dateLayouts are the formats tried, in order, when a cell is read as a date.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"1/2/2006 15:04:05",
+	"1/2/2006",
+}
+
+// parseDate parses s with the first matching layout in dateLayouts. A
+// date without a zone is read in loc, so it compares with bounds given in
+// that location as the calendar date it shows.
+func parseDate(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
 	}
+	return time.Time{}, false
+}
 
-	t := v.Type()
-	var result []interface{}
+// nowPlaceholder is the value returned by Now.
+type nowPlaceholder struct{}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+// Now returns a placeholder filter value that stands for the current time
+// when the query runs, so a query built once, such as
+// Where("ExpiresAt", "<", quire.Now()), stays correct when re-run later.
+func Now() interface{} {
+	return nowPlaceholder{}
+}
 
-		tag := fieldType.Tag.Get("quire")
-		if tag == "-" {
+// resolveNow returns q with every Now placeholder in its filters replaced
+// by now, so all rows are compared against the same instant. q itself is
+// returned if it has none.
+func (q *Query) resolveNow(now time.Time) *Query {
+	var filters []Filter
+	for i, f := range q.filters {
+		if _, ok := f.Value.(nowPlaceholder); !ok {
 			continue
 		}
-
-		result = append(result, field.Interface())
+		if filters == nil {
+			filters = append([]Filter{}, q.filters...)
+		}
+		filters[i].Value = now
+	}
+	if filters == nil {
+		return q
 	}
 
-	return result, nil
+	rq := *q
+	rq.filters = filters
+	return &rq
 }
 
-func scanIntoSlice(rows [][]interface{}, headers []interface{}, dest interface{}) error {
-	destVal := reflect.ValueOf(dest)
-	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
-		return fmt.Errorf("dest must be a pointer to a slice")
+// matchesTime compares a date cell with a time.Time filter value. handled
+// is false for operators that don't compare dates, which then fall back to
+// comparing text. Cells without a zone are read in t's location; cells
+// that don't parse as dates never match.
+func matchesTime(cellStr, op string, t time.Time) (matched, handled bool) {
+	switch op {
+	case "=", "==", "!=", ">", ">=", "<", "<=":
+	default:
+		return false, false
 	}
 
-	sliceVal := destVal.Elem()
-	elemType := sliceVal.Type().Elem()
+	cellTime, ok := parseDate(cellStr, t.Location())
+	if !ok {
+		return false, true
+	}
 
-	for _, row := range rows {
-		elem := reflect.New(elemType).Elem()
-		if err := scanRow(row, headers, elem); err != nil {
-			return err
-		}
-		sliceVal = reflect.Append(sliceVal, elem)
+	cmp := cellTime.Compare(t)
+	switch op {
+	case "=", "==":
+		return cmp == 0, true
+	case "!=":
+		return cmp != 0, true
+	case ">":
+		return cmp > 0, true
+	case ">=":
+		return cmp >= 0, true
+	case "<":
+		return cmp < 0, true
+	default:
+		return cmp <= 0, true
 	}
+}
 
-	destVal.Elem().Set(sliceVal)
-	return nil
+// emptyOperator reports whether op tests for an empty cell ("empty",
+// "isnull") rather than a present one ("notempty", "notnull"), and whether
+// it is one of these operators at all.
+func emptyOperator(op string) (empty, ok bool) {
+	switch op {
+	case "empty", "isnull":
+		return true, true
+	case "notempty", "notnull":
+		return false, true
+	}
+	return false, false
 }
 
-func scanRow(row []interface{}, headers []interface{}, dest reflect.Value) error {
-	if dest.Kind() == reflect.Ptr {
-		dest = dest.Elem()
+// hasColumn reports whether headers has column, or for a "column->path"
+// reference, the column the path is read from.
+func hasColumn(headers []interface{}, column string, lenient bool) bool {
+	if findColumnLenient(headers, column, lenient) != -1 {
+		return true
 	}
-	if dest.Kind() != reflect.Struct {
-		return fmt.Errorf("dest must be a struct")
+	name, _, ok := strings.Cut(column, "->")
+	return ok && findColumnLenient(headers, name, lenient) != -1
+}
+
+func equalText(a, b string, foldCase bool) bool {
+	if foldCase {
+		return strings.EqualFold(a, b)
 	}
+	return a == b
+}
 
-	t := dest.Type()
-	for i := 0; i < dest.NumField(); i++ {
-		field := dest.Field(i)
-		fieldType := t.Field(i)
+// compareNumbers compares a and b as numbers, for the "n" operators. ok
+// is false unless both parse as numbers, so such a filter never matches
+// text, unlike compareValues.
+func compareNumbers(a, b string) (c int, ok bool) {
+	aNum, aErr := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	bNum, bErr := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if aErr != nil || bErr != nil || math.IsNaN(aNum) || math.IsNaN(bNum) {
+		return 0, false
+	}
+	switch {
+	case aNum < bNum:
+		return -1, true
+	case aNum > bNum:
+		return 1, true
+	}
+	return 0, true
+}
 
-		tag := fieldType.Tag.Get("quire")
-		if tag == "-" {
-			continue
-		}
+// matchesComparison reports whether the result c of a three-way
+// comparison satisfies op, one of =, !=, >, >=, < and <=.
+func matchesComparison(op string, c int) bool {
+	switch op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
 
-		colName := fieldType.Name
-		if tag != "" {
-			colName = tag
-		}
+func compareValues(a, b interface{}) int {
+	aStr := fmt.Sprintf("%v", a)
+	bStr := fmt.Sprintf("%v", b)
 
-		colIdx := -1
-		for j, h := range headers {
-			if h == colName {
-				colIdx = j
-				break
-			}
-		}
+	// Try numeric comparison
+	aNum, aErr := strconv.ParseFloat(aStr, 64)
+	bNum, bErr := strconv.ParseFloat(bStr, 64)
 
-		if colIdx == -1 || colIdx >= len(row) {
-			continue
+	if aErr == nil && bErr == nil {
+		if aNum < bNum {
+			return -1
 		}
-
-		if err := setField(field, row[colIdx]); err != nil {
-			return fmt.Errorf("failed to set field %s: %w", fieldType.Name, err)
+		if aNum > bNum {
+			return 1
 		}
+		return 0
 	}
 
-	return nil
+	if aStr < bStr {
+		return -1
+	}
+	if aStr > bStr {
+		return 1
+	}
+	return 0
 }
 
-func setField(field reflect.Value, value interface{}) error {
-	if !field.CanSet() {
-		return nil
+func (q *Query) applySort(rows [][]interface{}, headers []interface{}) [][]interface{} {
+	colIdx := findColumnLenient(headers, q.orderBy, q.lenientHeaders())
+	if colIdx == -1 {
+		return rows
 	}
 
-	valueStr := fmt.Sprintf("%v", value)
+	sorted := make([][]interface{}, len(rows))
+	copy(sorted, rows)
+	parse := q.parsers[q.orderBy]
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return q.compareRows(sorted[i], sorted[j], colIdx, parse) < 0
+	})
+	return sorted
+}
 
-	switch field.Kind() {
-	case reflect.String:
-		field.SetString(valueStr)
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if i, err := strconv.ParseInt(valueStr, 10, 64); err == nil {
-			field.SetInt(i)
+// compareRows compares two rows by the order-by column at colIdx. With a
+// ParseColumn parser, cells it can read compare as the parsed numbers.
+func (q *Query) compareRows(a, b []interface{}, colIdx int, parse func(string) (float64, error)) int {
+	aVal, aBlank := sortCell(a, colIdx)
+	bVal, bBlank := sortCell(b, colIdx)
+	if parse != nil {
+		if f, ok := parseNumber(aVal, parse); ok && !aBlank {
+			aVal = f
 		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if i, err := strconv.ParseUint(valueStr, 10, 64); err == nil {
-			field.SetUint(i)
+		if f, ok := parseNumber(bVal, parse); ok && !bBlank {
+			bVal = f
 		}
-	case reflect.Float32, reflect.Float64:
-		if f, err := strconv.ParseFloat(valueStr, 64); err == nil {
-			field.SetFloat(f)
-		}
-	case reflect.Bool:
-		if b, err := strconv.ParseBool(valueStr); err == nil {
-			field.SetBool(b)
-		}
-	default:
-		if field.Kind() == reflect.Struct || field.Kind() == reflect.Slice {
-			data, _ := json.Marshal(value)
-			json.Unmarshal(data, field.Addr().Interface())
+	}
+
+	if q.nullsLast && (aBlank || bBlank) {
+		switch {
+		case aBlank && bBlank:
+			return 0
+		case aBlank:
+			return 1
+		default:
+			return -1
 		}
 	}
 
-	return nil
+	c := compareValues(aVal, bVal)
+	if q.descending {
+		c = -c
+	}
+	return c
+}
+
+// sortCell returns the cell used for ordering and whether it is blank.
+// Missing trailing cells are returned as an empty string.
+func sortCell(row []interface{}, colIdx int) (interface{}, bool) {
+	if colIdx >= len(row) || row[colIdx] == nil {
+		return "", true
+	}
+	return row[colIdx], fmt.Sprintf("%v", row[colIdx]) == ""
+}
+
+func (q *Query) applyLimit(rows [][]interface{}) [][]interface{} {
+	if q.percent > 0 {
+		n := int(math.Ceil(float64(len(rows)) * q.percent / 100))
+		rows = rows[:min(n, len(rows))]
+	}
+	if q.limit > 0 && q.limit < len(rows) {
+		return rows[:q.limit]
+	}
+	return rows
 }
@@ -3,7 +3,10 @@ package quire
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type TestUser struct {
@@ -44,12 +47,25 @@ func TestTable_Insert(t *testing.T) {
 			},
 			expectCall: true,
 		},
+		{
+			name: "insert pointer to slice",
+			records: &[]TestUser{
+				{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+			},
+			expectCall: true,
+		},
 		{
 			name:       "insert non-slice",
 			records:    TestUser{ID: 1, Name: "Alice"},
 			wantErr:    true,
 			expectCall: false,
 		},
+		{
+			name:       "insert pointer to struct",
+			records:    &TestUser{ID: 1, Name: "Alice"},
+			wantErr:    true,
+			expectCall: false,
+		},
 		{
 			name: "insert with error",
 			records: []TestUser{
@@ -99,6 +115,232 @@ func TestTable_Insert(t *testing.T) {
 	}
 }
 
+func TestTable_Insert_PointerToSlice(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	users := []TestUser{
+		{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+		{ID: 2, Name: "Bob", Email: "bob@test.com", Age: 25},
+	}
+	if err := table.Insert(ctx, users); err != nil {
+		t.Fatalf("Insert(slice) unexpected error = %v", err)
+	}
+	if err := table.Insert(ctx, &users); err != nil {
+		t.Fatalf("Insert(&slice) unexpected error = %v", err)
+	}
+
+	if len(mock.AppendCalls) != 2 {
+		t.Fatalf("Insert() made %d appends, want 2", len(mock.AppendCalls))
+	}
+	if !reflect.DeepEqual(mock.AppendCalls[0], mock.AppendCalls[1]) {
+		t.Errorf("Insert(&slice) appended %v, want the same as Insert(slice) %v",
+			mock.AppendCalls[1], mock.AppendCalls[0])
+	}
+}
+
+func TestTable_Insert_Batches(t *testing.T) {
+	ctx := context.Background()
+
+	records := make([]TestUser, 10000)
+	for i := range records {
+		records[i] = TestUser{ID: i + 1}
+	}
+
+	tests := []struct {
+		name      string
+		batchSize int
+		wantSizes []int
+	}{
+		{"default batch size", 0, []int{1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000}},
+		{"custom batch size", 3000, []int{3000, 3000, 3000, 1000}},
+		{"single batch", 20000, []int{10000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					return nil
+				},
+			}
+
+			db := &DB{client: mock, batchSize: tt.batchSize}
+			table := &Table{db: db, name: "Users"}
+
+			if err := table.Insert(ctx, records); err != nil {
+				t.Fatalf("Insert() unexpected error = %v", err)
+			}
+
+			var sizes []int
+			for _, call := range mock.AppendCalls {
+				sizes = append(sizes, len(call.Values))
+			}
+			if !reflect.DeepEqual(sizes, tt.wantSizes) {
+				t.Errorf("Insert() batch sizes = %v, want %v", sizes, tt.wantSizes)
+			}
+
+			last := mock.AppendCalls[len(mock.AppendCalls)-1].Values
+			if last[len(last)-1][0] != 10000 {
+				t.Errorf("Insert() last row ID = %v, want 10000", last[len(last)-1][0])
+			}
+		})
+	}
+}
+
+func TestTable_Insert_BatchError(t *testing.T) {
+	mock := &MockSheetsClient{
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return errors.New("append failed")
+		},
+	}
+
+	db := &DB{client: mock, batchSize: 2}
+	table := &Table{db: db, name: "Users"}
+
+	err := table.Insert(context.Background(), make([]TestUser, 5))
+	if err == nil {
+		t.Fatal("Insert() expected error")
+	}
+	if len(mock.AppendCalls) != 1 {
+		t.Errorf("Insert() should stop after the first failed batch, got %d calls", len(mock.AppendCalls))
+	}
+}
+
+func TestTable_Insert_AutoExpand(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		usedRows  int
+		gridRows  int
+		gridCols  int
+		wantCalls []string
+		wantGrow  ExpandSheetCall
+	}{
+		{
+			name:      "fits in grid",
+			usedRows:  3,
+			gridRows:  100,
+			gridCols:  26,
+			wantCalls: []string{"append"},
+		},
+		{
+			name:      "needs more rows",
+			usedRows:  9,
+			gridRows:  10,
+			gridCols:  26,
+			wantCalls: []string{"expand", "append"},
+			wantGrow:  ExpandSheetCall{SheetName: "Users", Rows: 1},
+		},
+		{
+			name:      "needs more columns",
+			usedRows:  1,
+			gridRows:  100,
+			gridCols:  2,
+			wantCalls: []string{"expand", "append"},
+			wantGrow:  ExpandSheetCall{SheetName: "Users", Columns: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			mock := &MockSheetsClient{
+				ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+					return []SheetProperties{
+						{SheetID: 1, Title: "Other", RowCount: 1, ColumnCount: 1},
+						{SheetID: 2, Title: "Users", RowCount: tt.gridRows, ColumnCount: tt.gridCols},
+					}, nil
+				},
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return make([][]interface{}, tt.usedRows), nil
+				},
+				ExpandSheetFunc: func(ctx context.Context, sheetName string, rows, columns int) error {
+					calls = append(calls, "expand")
+					return nil
+				},
+				AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+					calls = append(calls, "append")
+					return nil
+				},
+			}
+
+			db := &DB{client: mock, autoExpand: true}
+			table := &Table{db: db, name: "Users"}
+
+			records := []TestUser{{ID: 1}, {ID: 2}}
+			if err := table.Insert(ctx, records); err != nil {
+				t.Fatalf("Insert() unexpected error = %v", err)
+			}
+
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("Insert() calls = %v, want %v", calls, tt.wantCalls)
+			}
+			if len(mock.ExpandSheetCalls) > 0 && mock.ExpandSheetCalls[0] != tt.wantGrow {
+				t.Errorf("Insert() expand = %+v, want %+v", mock.ExpandSheetCalls[0], tt.wantGrow)
+			}
+			if mock.ReadCalls[0].Range_ != "Users" {
+				t.Errorf("Insert() used-rows read range = %v, want Users", mock.ReadCalls[0].Range_)
+			}
+		})
+	}
+}
+
+func TestTable_Insert_AutoExpand_BlankFirstColumn(t *testing.T) {
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{{SheetID: 2, Title: "Users", RowCount: 5, ColumnCount: 26}}, nil
+		},
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			// The last rows have no ID, so a read of column A alone would
+			// stop at row 2.
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice"},
+				{"", "Bob"},
+				{"", "Carol"},
+				{"", "", "dave@test.com"},
+			}, nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	table := (&DB{client: mock, autoExpand: true}).Table("Users")
+
+	if err := table.Insert(context.Background(), []TestUser{{ID: 5}, {ID: 6}}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	want := []ExpandSheetCall{{SheetName: "Users", Rows: 2}}
+	if !reflect.DeepEqual(mock.ExpandSheetCalls, want) {
+		t.Errorf("Insert() expand = %+v, want %+v", mock.ExpandSheetCalls, want)
+	}
+}
+
+func TestTable_Insert_AutoExpandDisabled(t *testing.T) {
+	mock := &MockSheetsClient{
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	if err := table.Insert(context.Background(), []TestUser{{ID: 1}}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	if mock.ListSheetsCalls != 0 || len(mock.ReadCalls) != 0 {
+		t.Error("Insert() without AutoExpand should not inspect the grid")
+	}
+}
+
 func TestTable_Query(t *testing.T) {
 	db := &DB{client: &MockSheetsClient{}}
 	table := &Table{db: db, name: "Users"}
@@ -326,3 +568,1387 @@ func TestQuery_Get_InvalidDest(t *testing.T) {
 		t.Error("Get() expected error for non-slice destination")
 	}
 }
+
+func TestQuery_First(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+				{2.0, "Bob", "bob@test.com", 25.0},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	var u TestUser
+	if err := table.Query().Where("ID", "=", 2).First(ctx, &u); err != nil {
+		t.Fatalf("First() unexpected error = %v", err)
+	}
+	want := TestUser{ID: 2, Name: "Bob", Email: "bob@test.com", Age: 25}
+	if u != want {
+		t.Errorf("First() = %+v, want %+v", u, want)
+	}
+
+	err := table.Query().Where("ID", "=", 3).First(ctx, &u)
+	if !errors.Is(err, ErrNoRows) {
+		t.Errorf("First() error = %v, want ErrNoRows", err)
+	}
+
+	if err := table.Query().First(ctx, u); err == nil {
+		t.Error("First() expected error for non-pointer destination")
+	}
+}
+
+func TestQuery_Reset(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+				{2.0, "Bob", "bob@test.com", 25.0},
+				{3.0, "Carol", "carol@test.com", 41.0},
+			}, nil
+		},
+	}
+	table := &Table{db: &DB{client: mock}, name: "Users"}
+
+	query := table.Query().
+		Where("Age", ">", 26).
+		Limit(1).
+		OrderBy("Age", true).
+		Select("Name").
+		StrictScan().
+		Timeout(time.Second).
+		Map(func(record interface{}) interface{} { return TestUser{} })
+
+	snapshot := *query
+	if result := query.Reset(); result != query {
+		t.Error("Reset() should return the same query for chaining")
+	}
+	if !reflect.DeepEqual(*query, Query{table: table}) {
+		t.Errorf("Reset() left state behind: %+v", *query)
+	}
+
+	var reset, fresh []TestUser
+	if err := query.Get(ctx, &reset); err != nil {
+		t.Fatalf("Get() after Reset() unexpected error = %v", err)
+	}
+	if err := table.Query().Get(ctx, &fresh); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(reset, fresh) {
+		t.Errorf("Get() after Reset() = %+v, want %+v", reset, fresh)
+	}
+
+	var users []TestUser
+	if err := query.Where("Name", "=", "Bob").Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "Bob" {
+		t.Errorf("reused query Get() = %+v, want only Bob", users)
+	}
+
+	// A copy taken before Reset keeps its own filters and mappers.
+	if len(snapshot.filters) != 1 || snapshot.filters[0].Column != "Age" {
+		t.Errorf("Reset() changed an earlier copy's filters: %+v", snapshot.filters)
+	}
+	if len(snapshot.mappers) != 1 || snapshot.mappers[0] == nil {
+		t.Errorf("Reset() cleared an earlier copy's mappers")
+	}
+}
+
+func TestQuery_GetPage(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+				{2.0, "Bob", "bob@test.com", 25.0},
+				{3.0, "Carol", "carol@test.com", 41.0},
+				{4.0, "Dave", "dave@test.com", 19.0},
+			}, nil
+		},
+	}
+	table := &Table{db: &DB{client: mock}, name: "Users"}
+
+	tests := []struct {
+		name        string
+		query       *Query
+		wantIDs     []int
+		wantHasMore bool
+	}{
+		{"more available", table.Query().Limit(2), []int{1, 2}, true},
+		{"exact page", table.Query().Where("Age", ">", 20).Limit(3), []int{1, 2, 3}, false},
+		{"partial page", table.Query().Where("Age", ">", 28).Limit(3), []int{1, 3}, false},
+		{"sorted", table.Query().OrderBy("Age", false).Limit(1), []int{4}, true},
+		{"no limit", table.Query(), []int{1, 2, 3, 4}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []TestUser
+			hasMore, err := tt.query.GetPage(ctx, &users)
+			if err != nil {
+				t.Fatalf("GetPage() unexpected error = %v", err)
+			}
+			var ids []int
+			for _, u := range users {
+				ids = append(ids, u.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("GetPage() IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			if hasMore != tt.wantHasMore {
+				t.Errorf("GetPage() hasMore = %v, want %v", hasMore, tt.wantHasMore)
+			}
+		})
+	}
+}
+
+func TestQuery_Map(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "alice", "alice@test.com", 30.0},
+				{2.0, "bob", "bob@test.com", 25.0},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	upper := func(record interface{}) interface{} {
+		u := record.(TestUser)
+		u.Name = strings.ToUpper(u.Name)
+		return u
+	}
+	older := func(record interface{}) interface{} {
+		u := record.(TestUser)
+		u.Age++
+		return u
+	}
+
+	var users []TestUser
+	if err := table.Query().Map(upper).Map(older).Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "ALICE" || users[1].Name != "BOB" || users[0].Age != 31 {
+		t.Errorf("Get() = %+v, want mapped names and ages", users)
+	}
+
+	var u TestUser
+	if err := table.Query().Where("ID", "=", 2).Map(upper).First(ctx, &u); err != nil {
+		t.Fatalf("First() unexpected error = %v", err)
+	}
+	if u.Name != "BOB" {
+		t.Errorf("First() Name = %q, want %q", u.Name, "BOB")
+	}
+
+	wrongType := func(record interface{}) interface{} {
+		return "not a user"
+	}
+	if err := table.Query().Map(wrongType).Get(ctx, &users); err == nil {
+		t.Error("Get() expected error when Map returns the wrong type")
+	}
+}
+
+func TestTable_ColumnWithRows(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("maps values to sheet rows", func(t *testing.T) {
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return [][]interface{}{
+					{"ID", "Email"},
+					{1.0, "alice@test.com"},
+					{2.0, ""},
+					{3.0},
+					{4.0, "dave@test.com"},
+				}, nil
+			},
+		}
+
+		db := &DB{client: mock}
+		table := &Table{db: db, name: "Users"}
+
+		got, err := table.ColumnWithRows(ctx, "Email")
+		if err != nil {
+			t.Fatalf("ColumnWithRows() unexpected error = %v", err)
+		}
+
+		want := map[int]string{2: "alice@test.com", 5: "dave@test.com"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ColumnWithRows() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return [][]interface{}{{"ID"}, {1.0}}, nil
+			},
+		}
+
+		db := &DB{client: mock}
+		table := &Table{db: db, name: "Users"}
+
+		if _, err := table.ColumnWithRows(ctx, "Email"); err == nil {
+			t.Error("ColumnWithRows() expected error for unknown column")
+		}
+	})
+
+	t.Run("empty sheet", func(t *testing.T) {
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return nil, nil
+			},
+		}
+
+		db := &DB{client: mock}
+		table := &Table{db: db, name: "Users"}
+
+		got, err := table.ColumnWithRows(ctx, "Email")
+		if err != nil {
+			t.Fatalf("ColumnWithRows() unexpected error = %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("ColumnWithRows() = %v, want empty", got)
+		}
+	})
+}
+
+func TestQuery_Count(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		mockData   [][]interface{}
+		mockError  error
+		setupQuery func(*Query)
+		want       int
+		wantErr    bool
+	}{
+		{
+			name: "unfiltered",
+			mockData: [][]interface{}{
+				{"ID", "Name", "Age"},
+				{1.0, "Alice", 30.0},
+				{2.0, "Bob", 25.0},
+				{3.0, "Charlie", 35.0},
+			},
+			want: 3,
+		},
+		{
+			name: "filtered ignores limit",
+			mockData: [][]interface{}{
+				{"ID", "Name", "Age"},
+				{1.0, "Alice", 30.0},
+				{2.0, "Bob", 25.0},
+				{3.0, "Charlie", 35.0},
+			},
+			setupQuery: func(q *Query) {
+				q.Where("Age", ">=", 30).Limit(1)
+			},
+			want: 2,
+		},
+		{
+			name:     "header only",
+			mockData: [][]interface{}{{"ID", "Name", "Age"}},
+			want:     0,
+		},
+		{
+			name:     "empty sheet",
+			mockData: nil,
+			want:     0,
+		},
+		{
+			name:      "read error",
+			mockError: errors.New("read failed"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return tt.mockData, tt.mockError
+				},
+			}
+
+			db := &DB{client: mock}
+			table := &Table{db: db, name: "Users"}
+			query := table.Query()
+
+			if tt.setupQuery != nil {
+				tt.setupQuery(query)
+			}
+
+			got, err := query.Count(ctx)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Count() expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Count() unexpected error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Count() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Distinct(t *testing.T) {
+	ctx := context.Background()
+	data := [][]interface{}{
+		{"ID", "Status", "Amount"},
+		{1.0, "open", 10.0},
+		{2.0, "closed", 25.0},
+		{3.0, "open", 1000000.0},
+		{4.0, nil, 5.0},
+		{5.0, "pending", 25.0},
+		{6.0, "closed"},
+	}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return data, nil
+		},
+	}
+	table := &Table{db: &DB{client: mock}, name: "Orders"}
+
+	tests := []struct {
+		name   string
+		query  *Query
+		column string
+		want   []string
+	}{
+		{"duplicates", table.Query(), "Status", []string{"open", "closed", "pending"}},
+		{"filtered", table.Query().Where("Amount", ">", 20), "Status", []string{"closed", "open", "pending"}},
+		{"sorted", table.Query().OrderBy("Status", true), "Status", []string{"pending", "open", "closed"}},
+		{"numbers", table.Query(), "Amount", []string{"10", "25", "1000000", "5"}},
+		{"no matches", table.Query().Where("ID", ">", 10), "Status", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Distinct(ctx, tt.column)
+			if err != nil {
+				t.Fatalf("Distinct() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distinct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := table.Query().Distinct(ctx, "Region"); err == nil || !strings.Contains(err.Error(), `column "Region" not found`) {
+		t.Errorf("Distinct() error = %v, want column not found", err)
+	}
+
+	data = [][]interface{}{}
+	got, err := table.Query().Distinct(ctx, "Status")
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Distinct() on empty sheet = %#v, %v, want empty slice", got, err)
+	}
+}
+
+func TestTable_Count(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name"},
+				{1.0, "Alice"},
+				{2.0, "Bob"},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	got, err := table.Count(context.Background())
+	if err != nil {
+		t.Fatalf("Count() unexpected error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("Count() = %d, want 2", got)
+	}
+}
+
+func TestQuery_Select_ProjectedRead(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			if range_ != "Users!1:1" {
+				t.Errorf("unexpected full read of %s", range_)
+			}
+			return [][]interface{}{{"ID", "Name", "Phone", "Age", "Email", "Notes"}}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users!B:B": {{"Name"}, {"Alice"}, {"Bob"}, {"Charlie"}},
+				"Users!D:E": {{"Age", "Email"}, {30.0, "alice@test.com"}, {25.0, "bob@test.com"}, {35.0}},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	var results []TestUser
+	err := table.Query().
+		Select("Name", "Email").
+		Where("Age", ">=", 30).
+		Get(ctx, &results)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+
+	if len(mock.BatchReadCalls) != 1 {
+		t.Fatalf("Get() expected 1 batch read, got %d", len(mock.BatchReadCalls))
+	}
+	wantRanges := []string{"Users!B:B", "Users!D:E"}
+	if !reflect.DeepEqual(mock.BatchReadCalls[0].Ranges, wantRanges) {
+		t.Errorf("Get() batch ranges = %v, want %v", mock.BatchReadCalls[0].Ranges, wantRanges)
+	}
+
+	// Age is read for the filter but not selected, so it isn't scanned.
+	want := []TestUser{
+		{Name: "Alice", Email: "alice@test.com"},
+		{Name: "Charlie"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Get() = %+v, want %+v", results, want)
+	}
+}
+
+func TestQuery_Select_ScansOnlySelected(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"ID", "Name", "Email", "Age"}}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users!B:B": {{"Name"}, {"Alice"}},
+				"Users!D:D": {{"Age"}, {30.0}},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	// Unknown columns are ignored rather than failing the query.
+	var u TestUser
+	err := table.Query().Select("Name", "Missing").Where("Age", ">", 18).First(ctx, &u)
+	if err != nil {
+		t.Fatalf("First() unexpected error = %v", err)
+	}
+	if want := (TestUser{Name: "Alice"}); u != want {
+		t.Errorf("First() = %+v, want %+v", u, want)
+	}
+}
+
+func TestQuery_Select_FallsBackToFullRead(t *testing.T) {
+	ctx := context.Background()
+	headers := []interface{}{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L"}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{headers}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Wide"}
+
+	var results []TestUser
+	err := table.Query().
+		Select("A", "C", "E", "G", "I", "K").
+		Get(ctx, &results)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+
+	if len(mock.BatchReadCalls) != 0 {
+		t.Errorf("Get() expected no batch read, got %d", len(mock.BatchReadCalls))
+	}
+	if len(mock.ReadCalls) != 2 || mock.ReadCalls[1].Range_ != "Wide" {
+		t.Errorf("Get() expected header read then full read, got %v", mock.ReadCalls)
+	}
+}
+
+func TestQuery_Select_EmptyBatchRead(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"ID", "Name", "Email", "Age"}}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			// The sheet was cleared between the header read and this one.
+			return map[string][][]interface{}{}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	var results []TestUser
+	if err := table.Query().Select("Name").Get(context.Background(), &results); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Get() = %+v, want no rows", results)
+	}
+}
+
+func TestQuery_Select_LenientHeaders(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"id", " Name ", "EMAIL", "age"}}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Users!B:B": {{" Name "}, {"Alice"}},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock, lenientHeaders: true}).Table("Users")
+
+	var results []TestUser
+	if err := table.Query().Select("Name").Get(context.Background(), &results); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(mock.ReadCalls) != 1 || len(mock.BatchReadCalls) != 1 {
+		t.Fatalf("Get() made %d reads and %d batch reads, want a projected read", len(mock.ReadCalls), len(mock.BatchReadCalls))
+	}
+	if want := []TestUser{{Name: "Alice"}}; !reflect.DeepEqual(results, want) {
+		t.Errorf("Get() = %+v, want %+v", results, want)
+	}
+}
+
+func TestColumnRuns(t *testing.T) {
+	got := columnRuns([]int{4, 1, 3, 1, 7})
+	want := [][2]int{{1, 1}, {3, 4}, {7, 7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("columnRuns() = %v, want %v", got, want)
+	}
+}
+
+func TestTable_DetectHeaderChanges(t *testing.T) {
+	ctx := context.Background()
+	headers := []interface{}{"ID", "Name", "Email", "Age"}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				headers,
+				{1.0, "Alice", "alice@test.com", 30.0},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := db.TableWithOptions("Users", TableOptions{DetectHeaderChanges: true})
+
+	var results []TestUser
+	if err := table.Query().Get(ctx, &results); err != nil {
+		t.Fatalf("first Get() unexpected error = %v", err)
+	}
+
+	if _, err := table.Query().Count(ctx); err != nil {
+		t.Fatalf("Count() with unchanged header unexpected error = %v", err)
+	}
+
+	headers = []interface{}{"ID", "Email", "Name", "Age"}
+
+	err := table.Query().Get(ctx, &results)
+	if !errors.Is(err, ErrHeaderChanged) {
+		t.Fatalf("Get() after header change error = %v, want ErrHeaderChanged", err)
+	}
+
+	if err := table.Query().Get(ctx, &results); err != nil {
+		t.Errorf("Get() after refresh unexpected error = %v", err)
+	}
+}
+
+func TestTable_DetectHeaderChanges_Disabled(t *testing.T) {
+	ctx := context.Background()
+	headers := []interface{}{"ID", "Name"}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{headers}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := db.Table("Users")
+
+	var results []TestUser
+	_ = table.Query().Get(ctx, &results)
+	headers = []interface{}{"Name", "ID"}
+
+	if err := table.Query().Get(ctx, &results); err != nil {
+		t.Errorf("Get() without detection unexpected error = %v", err)
+	}
+}
+
+func TestQuery_Timeout(t *testing.T) {
+	slowRead := func(ctx context.Context, range_ string) ([][]interface{}, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return [][]interface{}{{"ID"}, {1.0}}, nil
+		}
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		db := &DB{client: &MockSheetsClient{ReadFunc: slowRead}}
+		table := &Table{db: db, name: "Users"}
+
+		var results []TestUser
+		err := table.Query().Timeout(10*time.Millisecond).Get(context.Background(), &results)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Get() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		db := &DB{client: &MockSheetsClient{ReadFunc: slowRead}}
+		table := &Table{db: db, name: "Users"}
+
+		_, err := table.Query().Timeout(10 * time.Millisecond).Count(context.Background())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Count() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("context cancelled after query", func(t *testing.T) {
+		var readCtx context.Context
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				readCtx = ctx
+				return [][]interface{}{{"ID"}, {1.0}}, nil
+			},
+		}
+		db := &DB{client: mock}
+		table := &Table{db: db, name: "Users"}
+
+		var results []TestUser
+		if err := table.Query().Timeout(time.Minute).Get(context.Background(), &results); err != nil {
+			t.Fatalf("Get() unexpected error = %v", err)
+		}
+		if readCtx.Err() != context.Canceled {
+			t.Errorf("derived context error = %v, want context.Canceled", readCtx.Err())
+		}
+	})
+}
+
+func TestTable_Columns(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Age"},
+				{1.0, "Alice", 30.0},
+				{2.0, "Bob"},
+				{3.0, "", 35.0},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := &Table{db: db, name: "Users"}
+
+	got, err := table.Columns(ctx, "Name", "Age")
+	if err != nil {
+		t.Fatalf("Columns() unexpected error = %v", err)
+	}
+
+	want := map[string][]string{
+		"Name": {"Alice", "Bob", ""},
+		"Age":  {"30", "", "35"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+
+	if len(mock.ReadCalls) != 1 {
+		t.Errorf("Columns() expected 1 read, got %d", len(mock.ReadCalls))
+	}
+
+	if _, err := table.Columns(ctx, "Name", "Missing"); err == nil {
+		t.Error("Columns() expected error for unknown column")
+	}
+}
+
+func TestTable_Anchor(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			if range_ == "Users!3:3" {
+				return [][]interface{}{{"", "ID", "Name", "Email", "Age"}}, nil
+			}
+			return [][]interface{}{
+				{"Quarterly report"},
+				{},
+				{"", "ID", "Name", "Email", "Age"},
+				{"note", 1.0, "Alice", "alice@example.com", 30.0},
+				{"", 2.0, "Bob", "bob@example.com", 25.0},
+			}, nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	db := &DB{client: mock}
+	table := db.TableWithOptions("Users", TableOptions{Anchor: "B3"})
+
+	var users []TestUser
+	if err := table.Query().Where("Age", ">", 26).Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	want := []TestUser{{ID: 1, Name: "Alice", Email: "alice@example.com", Age: 30}}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("Get() = %+v, want %+v", users, want)
+	}
+
+	if err := table.Insert(ctx, []TestUser{{ID: 3, Name: "Carol"}}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	if got := mock.AppendCalls[0].Range_; got != "Users!B3" {
+		t.Errorf("Insert() range = %q, want %q", got, "Users!B3")
+	}
+
+	if err := table.Update(ctx, 1, TestUser{ID: 2, Name: "Robert"}); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if got := mock.WriteCalls[0].Range_; got != "Users!B5:E5" {
+		t.Errorf("Update() range = %q, want %q", got, "Users!B5:E5")
+	}
+
+	if err := table.DeleteWhere(ctx, "Name", "=", "Alice"); err != nil {
+		t.Fatalf("DeleteWhere() unexpected error = %v", err)
+	}
+	if got := mock.DeleteRowsCalls[0].RowIndices; !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("DeleteWhere() indices = %v, want [3]", got)
+	}
+
+	rows, err := table.ColumnWithRows(ctx, "Name")
+	if err != nil {
+		t.Fatalf("ColumnWithRows() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(rows, map[int]string{4: "Alice", 5: "Bob"}) {
+		t.Errorf("ColumnWithRows() = %v", rows)
+	}
+}
+
+func TestTable_WithHeaderRow(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"Team roster"},
+				{"Updated weekly"},
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@example.com", 30.0},
+				{2.0, "Bob", "bob@example.com", 25.0},
+				{3.0, "Carol", "carol@example.com", 35.0},
+			}, nil
+		},
+		BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+			return nil
+		},
+	}
+
+	table := (&DB{client: mock}).Table("Users").WithHeaderRow(3)
+
+	var users []TestUser
+	if err := table.Query().Where("Age", ">", 26).Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	want := []TestUser{
+		{ID: 1, Name: "Alice", Email: "alice@example.com", Age: 30},
+		{ID: 3, Name: "Carol", Email: "carol@example.com", Age: 35},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("Get() = %+v, want %+v", users, want)
+	}
+
+	if err := table.UpdateWhere(ctx, "Name", "=", "Bob", TestUser{ID: 2, Name: "Robert"}); err != nil {
+		t.Fatalf("UpdateWhere() unexpected error = %v", err)
+	}
+	var ranges []string
+	for r := range mock.BatchWriteCalls[0].Data {
+		ranges = append(ranges, r)
+	}
+	if !reflect.DeepEqual(ranges, []string{"Users!A5:D5"}) {
+		t.Errorf("UpdateWhere() ranges = %v, want [Users!A5:D5]", ranges)
+	}
+
+	if err := table.DeleteWhere(ctx, "Age", ">", 26); err != nil {
+		t.Fatalf("DeleteWhere() unexpected error = %v", err)
+	}
+	if got := mock.DeleteRowsCalls[0].RowIndices; !reflect.DeepEqual(got, []int{5, 3}) {
+		t.Errorf("DeleteWhere() indices = %v, want [5 3]", got)
+	}
+
+	anchored := (&DB{client: mock}).TableWithOptions("Users", TableOptions{Anchor: "C1"}).WithHeaderRow(0)
+	if anchored.opts.Anchor != "C1" {
+		t.Errorf("WithHeaderRow(0) anchor = %q, want C1", anchored.opts.Anchor)
+	}
+}
+
+func TestParseCellRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		col, row int
+		ok       bool
+	}{
+		{"A1", 0, 1, true},
+		{"B3", 1, 3, true},
+		{"aa10", 26, 10, true},
+		{"C", 2, 1, true},
+		{"5", 0, 5, true},
+		{"", 0, 0, false},
+		{"B0", 0, 0, false},
+		{"3B", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		col, row, ok := parseCellRef(tt.ref)
+		if col != tt.col || row != tt.row || ok != tt.ok {
+			t.Errorf("parseCellRef(%q) = %d, %d, %v, want %d, %d, %v", tt.ref, col, row, ok, tt.col, tt.row, tt.ok)
+		}
+	}
+}
+
+func TestTable_Size(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{
+				{SheetID: 0, Title: "Orders", RowCount: 5000, ColumnCount: 12},
+				{SheetID: 1, Title: "Users", RowCount: 1000, ColumnCount: 26},
+			}, nil
+		},
+	}
+
+	db := &DB{client: mock}
+
+	rows, cols, err := db.Table("Users").Size(ctx)
+	if err != nil {
+		t.Fatalf("Size() unexpected error = %v", err)
+	}
+	if rows != 1000 || cols != 26 {
+		t.Errorf("Size() = %d, %d, want 1000, 26", rows, cols)
+	}
+	if len(mock.ReadCalls) != 0 {
+		t.Errorf("Size() should not read cells, got %d reads", len(mock.ReadCalls))
+	}
+
+	if _, _, err := db.Table("Missing").Size(ctx); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("Size() error = %v, want ErrSheetNotFound", err)
+	}
+}
+
+func TestTable_MaxScanColumn(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		opts       TableOptions
+		wantRange  string
+		wantHeader string
+	}{
+		{"unbounded", TableOptions{}, "Users", "Users!1:1"},
+		{"bounded", TableOptions{MaxScanColumn: "z"}, "Users!A:Z", "Users!A1:Z1"},
+		{"bounded and anchored", TableOptions{MaxScanColumn: "H", Anchor: "B3"}, "Users!A:H", "Users!A3:H3"},
+		{"invalid bound ignored", TableOptions{MaxScanColumn: "Z9"}, "Users", "Users!1:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return [][]interface{}{
+						{"ID", "Name", "Email", "Age"},
+						{1.0, "Alice", "alice@test.com", 30.0},
+					}, nil
+				},
+				ClearFunc: func(ctx context.Context, range_ string) error {
+					return nil
+				},
+			}
+
+			db := &DB{client: mock}
+			table := db.TableWithOptions("Users", tt.opts)
+
+			var users []TestUser
+			if err := table.Query().Get(ctx, &users); err != nil {
+				t.Fatalf("Get() unexpected error = %v", err)
+			}
+			if got := mock.ReadCalls[0].Range_; got != tt.wantRange {
+				t.Errorf("Get() range = %q, want %q", got, tt.wantRange)
+			}
+
+			if err := table.Truncate(ctx); err != nil {
+				t.Fatalf("Truncate() unexpected error = %v", err)
+			}
+			if got := mock.ReadCalls[1].Range_; got != tt.wantHeader {
+				t.Errorf("header range = %q, want %q", got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestNormalizeHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []interface{}
+		want    []interface{}
+	}{
+		{
+			name:    "padded",
+			headers: []interface{}{" ID", "Name  ", "\tEmail\n"},
+			want:    []interface{}{"ID", "Name", "Email"},
+		},
+		{
+			name:    "duplicates",
+			headers: []interface{}{"Name", "Name ", "Age", " Name"},
+			want:    []interface{}{"Name", "Name_2", "Age", "Name_3"},
+		},
+		{
+			name:    "suffix already taken",
+			headers: []interface{}{"Name", "Name", "Name_2"},
+			want:    []interface{}{"Name", "Name_3", "Name_2"},
+		},
+		{
+			name:    "blanks and numbers untouched",
+			headers: []interface{}{"", " ", 2024.0, 2024.0},
+			want:    []interface{}{"", "", 2024.0, 2024.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeHeaders(tt.headers)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeHeaders(%q) = %q, want %q", tt.headers, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_NormalizeHeaders(t *testing.T) {
+	type Contact struct {
+		Name      string `quire:"Name"`
+		OtherName string `quire:"Name_2"`
+		Email     string `quire:"Email"`
+	}
+
+	data := [][]interface{}{
+		{" Name ", "Name", "Email  "},
+		{"Alice", "Ally", "alice@test.com"},
+	}
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return data, nil
+		},
+	}
+
+	var got []Contact
+	db := &DB{client: mock, normalizeHeaders: true}
+	if err := db.Table("Contacts").Query().Get(context.Background(), &got); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	want := []Contact{{Name: "Alice", OtherName: "Ally", Email: "alice@test.com"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+	if data[0][0] != " Name " {
+		t.Errorf("normalization modified the read data: %q", data[0])
+	}
+
+	// Without the option, the padded headers don't match the tags.
+	got = nil
+	if err := (&DB{client: mock}).Table("Contacts").Query().Get(context.Background(), &got); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if got[0].Email != "" {
+		t.Errorf("Get() without NormalizeHeaders = %+v, want Email unmapped", got[0])
+	}
+}
+
+func TestQuery_ByPosition(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("meaningless headers", func(t *testing.T) {
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return [][]interface{}{
+					{"", "col?", "", "x"},
+					{1.0, "Alice", "alice@test.com", 30.0},
+					{2.0, "Bob", "bob@test.com", 25.0},
+				}, nil
+			},
+		}
+		table := (&DB{client: mock}).Table("Raw")
+
+		var byName []TestUser
+		if err := table.Query().Get(ctx, &byName); err != nil {
+			t.Fatalf("Get() unexpected error = %v", err)
+		}
+		if byName[0] != (TestUser{}) {
+			t.Errorf("Get() by name = %+v, want zero values", byName[0])
+		}
+
+		var users []TestUser
+		if err := table.Query().ByPosition().Get(ctx, &users); err != nil {
+			t.Fatalf("Get() unexpected error = %v", err)
+		}
+		want := []TestUser{
+			{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+			{ID: 2, Name: "Bob", Email: "bob@test.com", Age: 25},
+		}
+		if !reflect.DeepEqual(users, want) {
+			t.Errorf("Get() by position = %+v, want %+v", users, want)
+		}
+	})
+
+	t.Run("position wins over header names", func(t *testing.T) {
+		type Pair struct {
+			Name  string `quire:"Name"`
+			Email string `quire:"Email"`
+			Note  string `quire:"Note,col:D"`
+		}
+
+		mock := &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return [][]interface{}{
+					{"Email", "Name", "Note", "Extra"},
+					{"bob@test.com", "Bob", "not me", "note"},
+				}, nil
+			},
+		}
+
+		var got Pair
+		err := (&DB{client: mock}).Table("Pairs").Query().ByPosition().First(ctx, &got)
+		if err != nil {
+			t.Fatalf("First() unexpected error = %v", err)
+		}
+		want := Pair{Name: "bob@test.com", Email: "Bob", Note: "note"}
+		if got != want {
+			t.Errorf("First() = %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestQuery_LenientHeaders(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"id", " Name ", "EMAIL", "age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+				{2.0, "Bob", "bob@test.com", 25.0},
+				{3.0, "Carol", "carol@test.com", 35.0},
+			}, nil
+		},
+	}
+
+	var users []TestUser
+	lenient := &DB{client: mock, lenientHeaders: true}
+	err := lenient.Table("Users").Query().
+		Where("Email", "!=", "bob@test.com").
+		OrderBy("Age", true).
+		Get(ctx, &users)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	want := []TestUser{
+		{ID: 3, Name: "Carol", Email: "carol@test.com", Age: 35},
+		{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("lenient Get() = %+v, want %+v", users, want)
+	}
+
+	users = nil
+	strict := &DB{client: mock}
+	if err := strict.Table("Users").Query().Get(ctx, &users); err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if users[0] != (TestUser{}) {
+		t.Errorf("Get() without LenientHeaders = %+v, want zero values", users[0])
+	}
+
+	n, err := strict.Table("Users").Query().Where("Email", "=", "bob@test.com").Count(ctx)
+	if err != nil || n != 0 {
+		t.Errorf("Count() without LenientHeaders = %d, %v, want 0", n, err)
+	}
+}
+
+func TestTable_LenientHeaders_Writes(t *testing.T) {
+	ctx := context.Background()
+	sheet := [][]interface{}{
+		{"id", " Name ", " Email ", "AGE"},
+		{1.0, "Alice", "alice@test.com", 30.0},
+		{2.0, "Bob", "bob@test.com", 25.0},
+	}
+	newMock := func() *MockSheetsClient {
+		return &MockSheetsClient{
+			ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+				return sheet, nil
+			},
+			WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+				return nil
+			},
+			BatchWriteFunc: func(ctx context.Context, data map[string][][]interface{}) error {
+				return nil
+			},
+			AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+				return nil
+			},
+		}
+	}
+	record := TestUser{ID: 2, Name: "Bobby", Email: "bobby@test.com", Age: 26}
+	want := []interface{}{2, "Bobby", "bobby@test.com", 26}
+
+	mock := newMock()
+	table := (&DB{client: mock, lenientHeaders: true}).Table("Users")
+	if err := table.Update(ctx, 1, record); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if len(mock.WriteCalls) != 1 || !reflect.DeepEqual(mock.WriteCalls[0].Values[0], want) {
+		t.Errorf("Update() wrote %+v, want %v", mock.WriteCalls, want)
+	}
+
+	mock = newMock()
+	table = (&DB{client: mock, lenientHeaders: true}).Table("Users")
+	if err := table.Upsert(ctx, "Email", TestUser{ID: 1, Name: "Alicia", Email: "alice@test.com", Age: 31}); err != nil {
+		t.Fatalf("Upsert() unexpected error = %v", err)
+	}
+	if len(mock.AppendCalls) != 0 {
+		t.Errorf("Upsert() appended %+v, want the matching row updated", mock.AppendCalls)
+	}
+	if written := len(mock.WriteCalls) + len(mock.BatchWriteCalls); written != 1 {
+		t.Errorf("Upsert() made %d writes, want 1", written)
+	}
+
+	mock = newMock()
+	table = (&DB{client: mock, lenientHeaders: true}).Table("Users")
+	if err := table.UpdateWhere(ctx, "Email", "=", "bob@test.com", record); err != nil {
+		t.Fatalf("UpdateWhere() unexpected error = %v", err)
+	}
+	if len(mock.BatchWriteCalls) != 1 || !reflect.DeepEqual(mock.BatchWriteCalls[0].Data["Users!A3:D3"], [][]interface{}{want}) {
+		t.Errorf("UpdateWhere() wrote %+v, want %v at Users!A3:D3", mock.BatchWriteCalls, want)
+	}
+
+	// Without LenientHeaders no header matches exactly, so every field is
+	// dropped.
+	mock = newMock()
+	table = (&DB{client: mock}).Table("Users")
+	if err := table.Update(ctx, 1, record); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if got := mock.WriteCalls[0].Values[0]; !reflect.DeepEqual(got, []interface{}{nil}) {
+		t.Errorf("Update() without LenientHeaders wrote %v, want no fields", got)
+	}
+}
+
+func TestFindColumnLenient(t *testing.T) {
+	headers := []interface{}{"name", " Name ", 7.0, "EMAIL"}
+
+	tests := []struct {
+		name    string
+		lenient bool
+		want    int
+	}{
+		{"name", false, 0},
+		{"Name", false, -1},
+		{"Name", true, 0},
+		{" Name ", true, 1},
+		{"email", true, 3},
+		{"Email ", true, 3},
+		{"Phone", true, -1},
+	}
+	for _, tt := range tests {
+		if got := findColumnLenient(headers, tt.name, tt.lenient); got != tt.want {
+			t.Errorf("findColumnLenient(%q, %v) = %d, want %d", tt.name, tt.lenient, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_Strict(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age", "Meta"},
+				{1.0, "Alice", "alice@test.com", 30.0, `{"role":"admin"}`},
+			}, nil
+		},
+	}
+	table := (&DB{client: mock}).Table("Users")
+
+	var users []TestUser
+	err := table.Query().Strict().
+		Where("Naem", "=", "Alice").
+		Where("Age", ">", 18).
+		OrderBy("Agee", false).
+		Get(ctx, &users)
+	if err == nil {
+		t.Fatal("Get() expected error for unknown columns")
+	}
+	if want := `query references unknown columns: "Naem", "Agee"`; err.Error() != want {
+		t.Errorf("Get() error = %q, want %q", err, want)
+	}
+
+	_, err = table.Query().Strict().Where("Emial", "=", "x").Count(ctx)
+	if err == nil || !strings.Contains(err.Error(), `"Emial"`) {
+		t.Errorf("Count() error = %v, want it to name Emial", err)
+	}
+
+	// Known columns, including JSON paths, pass.
+	err = table.Query().Strict().Where("Meta->role", "=", "admin").OrderBy("Name", false).Get(ctx, &users)
+	if err != nil || len(users) != 1 {
+		t.Errorf("Get() = %v, %v, want 1 user", users, err)
+	}
+
+	// Without Strict, a typo silently matches nothing.
+	users = nil
+	if err := table.Query().Where("Naem", "=", "Alice").Get(ctx, &users); err != nil || len(users) != 0 {
+		t.Errorf("relaxed Get() = %v, %v, want no rows and no error", users, err)
+	}
+}
+
+func TestQuery_SpilledRows(t *testing.T) {
+	// Rows as the API returns them for a sheet where an array formula in C2
+	// spills a varying number of tag columns: trailing blanks are left out,
+	// so no two rows have the same width, and spilled cells run past the
+	// last header.
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Tag"},
+				{1.0, "Alice", "admin", "ops", "oncall"},
+				{2.0, "Bob"},
+				{3.0},
+				{4.0, "Dave", "ops"},
+			}, nil
+		},
+	}
+
+	type tagged struct {
+		ID    int      `quire:"ID"`
+		Name  string   `quire:"Name"`
+		Tag   string   `quire:"Tag"`
+		Cells []string `quire:",raw"`
+	}
+
+	var got []tagged
+	err := (&DB{client: mock}).Table("Users").Query().OrderBy("Tag", false).Get(context.Background(), &got)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+
+	want := []tagged{
+		{ID: 2, Name: "Bob", Cells: []string{"2", "Bob", "", "", ""}},
+		{ID: 3, Cells: []string{"3", "", "", "", ""}},
+		{ID: 1, Name: "Alice", Tag: "admin", Cells: []string{"1", "Alice", "admin", "ops", "oncall"}},
+		{ID: 4, Name: "Dave", Tag: "ops", Cells: []string{"4", "Dave", "ops", "", ""}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPadRows(t *testing.T) {
+	header := []interface{}{"A", "B", "C"}
+	short := []interface{}{1.0}
+	wide := []interface{}{1.0, 2.0, 3.0, 4.0}
+	data := [][]interface{}{header, short, wide}
+
+	got := padRows(data)
+	want := [][]interface{}{header, {1.0, nil, nil, nil}, wide}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("padRows() = %v, want %v", got, want)
+	}
+	if len(short) != 1 || len(data[1]) != 1 {
+		t.Error("padRows() modified its input")
+	}
+
+	even := [][]interface{}{header, {1.0, 2.0, 3.0}}
+	if got := padRows(even); &got[0] != &even[0] {
+		t.Error("padRows() copied rows that were already even")
+	}
+}
+
+func TestTable_Headers(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]interface{}
+		want []string
+	}{
+		{"normal header", [][]interface{}{{"ID", "Name", "Email"}}, []string{"ID", "Name", "Email"}},
+		{"empty sheet", nil, []string{}},
+		{"blank cells", [][]interface{}{{"ID", "", "Email", nil, 2024.0}}, []string{"ID", "", "Email", "", "2024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &MockSheetsClient{
+				ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+					return tt.data, nil
+				},
+			}
+			table := (&DB{client: mock}).Table("Users")
+
+			got, err := table.Headers(context.Background())
+			if err != nil {
+				t.Fatalf("Headers() unexpected error = %v", err)
+			}
+			if got == nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Headers() = %#v, want %#v", got, tt.want)
+			}
+			if len(mock.ReadCalls) != 1 || mock.ReadCalls[0].Range_ != "Users!1:1" {
+				t.Errorf("Headers() read %v, want only the header row", mock.ReadCalls)
+			}
+		})
+	}
+}
+
+func TestTable_Headers_HeaderRow(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{{"Report title", "SKU", "Price"}}, nil
+		},
+	}
+	table := (&DB{client: mock}).TableWithOptions("Products", TableOptions{Anchor: "B3"})
+
+	got, err := table.Headers(context.Background())
+	if err != nil {
+		t.Fatalf("Headers() unexpected error = %v", err)
+	}
+	if want := []string{"SKU", "Price"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Headers() = %v, want %v", got, want)
+	}
+	if mock.ReadCalls[0].Range_ != "Products!3:3" {
+		t.Errorf("Headers() read %s, want Products!3:3", mock.ReadCalls[0].Range_)
+	}
+}
@@ -0,0 +1,126 @@
+package quire
+
+import (
+	"context"
+	"time"
+)
+
+// timeoutClient wraps a SheetsClient and bounds every call with a
+// deadline derived from the caller's context.
+type timeoutClient struct {
+	next    SheetsClient
+	timeout time.Duration
+}
+
+func newTimeoutClient(next SheetsClient, timeout time.Duration) *timeoutClient {
+	return &timeoutClient{next: next, timeout: timeout}
+}
+
+// withTimeout derives the context for a single call. A parent context that
+// is already done is returned as an error without starting the call.
+func (c *timeoutClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	return ctx, cancel, nil
+}
+
+func (c *timeoutClient) Read(ctx context.Context, range_ string) ([][]interface{}, error) {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return c.next.Read(ctx, range_)
+}
+
+func (c *timeoutClient) BatchRead(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return c.next.BatchRead(ctx, ranges)
+}
+
+func (c *timeoutClient) Write(ctx context.Context, range_ string, values [][]interface{}) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.Write(ctx, range_, values)
+}
+
+func (c *timeoutClient) BatchWrite(ctx context.Context, data map[string][][]interface{}) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.BatchWrite(ctx, data)
+}
+
+func (c *timeoutClient) Append(ctx context.Context, range_ string, values [][]interface{}) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.Append(ctx, range_, values)
+}
+
+func (c *timeoutClient) Clear(ctx context.Context, range_ string) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.Clear(ctx, range_)
+}
+
+func (c *timeoutClient) DeleteRows(ctx context.Context, sheetName string, rowIndices []int) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.DeleteRows(ctx, sheetName, rowIndices)
+}
+
+func (c *timeoutClient) ListSheets(ctx context.Context) ([]SheetProperties, error) {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return c.next.ListSheets(ctx)
+}
+
+func (c *timeoutClient) AddSheet(ctx context.Context, sheetName string) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.AddSheet(ctx, sheetName)
+}
+
+func (c *timeoutClient) DeleteSheet(ctx context.Context, sheetName string) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.DeleteSheet(ctx, sheetName)
+}
+
+func (c *timeoutClient) ExpandSheet(ctx context.Context, sheetName string, rows, columns int) error {
+	ctx, cancel, err := c.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return c.next.ExpandSheet(ctx, sheetName, rows, columns)
+}
@@ -0,0 +1,76 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeoutClient_DeadlineExceeded(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return nil, nil
+			}
+		},
+	}
+
+	db := &DB{client: newTimeoutClient(mock, 10*time.Millisecond)}
+
+	var results []TestUser
+	err := db.Table("Users").Query().Get(context.Background(), &results)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestTimeoutClient_CancelledParent(t *testing.T) {
+	mock := &MockSheetsClient{
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+
+	client := newTimeoutClient(mock, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.Append(ctx, "Users!A1", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Append() error = %v, want context.Canceled", err)
+	}
+	if len(mock.AppendCalls) != 0 {
+		t.Errorf("Append() should not reach the client, got %d calls", len(mock.AppendCalls))
+	}
+}
+
+func TestTimeoutClient_ReleasesContext(t *testing.T) {
+	var callCtx context.Context
+	mock := &MockSheetsClient{
+		WriteFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			callCtx = ctx
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("Write() context has no deadline")
+			}
+			return nil
+		},
+	}
+
+	client := newTimeoutClient(mock, time.Minute)
+	if err := client.Write(context.Background(), "Users!A2", nil); err != nil {
+		t.Fatalf("Write() unexpected error = %v", err)
+	}
+
+	if callCtx.Err() != context.Canceled {
+		t.Errorf("derived context error = %v, want context.Canceled after return", callCtx.Err())
+	}
+}
+
+func TestTimeoutClientInterface(t *testing.T) {
+	var _ SheetsClient = (*timeoutClient)(nil)
+}
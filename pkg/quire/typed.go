@@ -0,0 +1,256 @@
+package quire
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Get runs q and returns the results as a []T, so callers don't declare
+// a slice to pass to Query.Get:
+//
+//	users, err := quire.Get[User](ctx, db.Table("Users").Query().Where("Age", ">", 18))
+func Get[T any](ctx context.Context, q *Query) ([]T, error) {
+	var results []T
+	err := q.Get(ctx, &results)
+	return results, err
+}
+
+// First runs q and returns the first matching record, or the zero value
+// and ErrNoRows if nothing matches.
+func First[T any](ctx context.Context, q *Query) (T, error) {
+	var result T
+	err := q.First(ctx, &result)
+	return result, err
+}
+
+// Stream runs q and calls fn with each matching record in turn, stopping
+// at and returning the first error fn returns. Rows are scanned one at a
+// time as fn consumes them, though the sheet itself is still read in one
+// request. With StrictScan, a row that fails to scan stops the stream
+// with its ScanErrors.
+func Stream[T any](ctx context.Context, q *Query, fn func(T) error) error {
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	headers, rows, err := q.rows(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := q.scanner()
+	for i, row := range q.applyLimit(rows) {
+		var record T
+		elem := reflect.ValueOf(&record).Elem()
+		if err := s.scanRow(row, headers, elem); err != nil {
+			if scanErrs, ok := err.(ScanErrors); ok {
+				for _, e := range scanErrs {
+					e.Row = i
+				}
+			}
+			return err
+		}
+		if err := q.applyMappers(elem); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TypedTable is a Table whose rows are read into and written from values
+// of the struct type T, so results come back as []T instead of being
+// scanned into a destination passed by pointer.
+type TypedTable[T any] struct {
+	table *Table
+}
+
+// Typed returns a typed view of t. T must be a struct type mapped with
+// quire tags, as for Get.
+func Typed[T any](t *Table) *TypedTable[T] {
+	return &TypedTable[T]{table: t}
+}
+
+// Table returns the underlying untyped table.
+func (tt *TypedTable[T]) Table() *Table {
+	return tt.table
+}
+
+// Query starts a typed query on the table.
+func (tt *TypedTable[T]) Query() *TypedQuery[T] {
+	return &TypedQuery[T]{query: tt.table.Query()}
+}
+
+// GetAll returns every row of the table.
+func (tt *TypedTable[T]) GetAll(ctx context.Context) ([]T, error) {
+	return tt.Query().Get(ctx)
+}
+
+// First returns the first row of the table, or ErrNoRows if it is empty.
+func (tt *TypedTable[T]) First(ctx context.Context) (T, error) {
+	return tt.Query().First(ctx)
+}
+
+// Insert appends records to the table.
+func (tt *TypedTable[T]) Insert(ctx context.Context, records []T) error {
+	return tt.table.Insert(ctx, records)
+}
+
+// TypedQuery is a Query whose results are values of type T. Its builder
+// methods mirror Query's.
+type TypedQuery[T any] struct {
+	query *Query
+}
+
+// Query returns the underlying untyped query.
+func (q *TypedQuery[T]) Query() *Query {
+	return q.query
+}
+
+// Where adds a filter condition; see Query.Where.
+func (q *TypedQuery[T]) Where(column, operator string, value interface{}) *TypedQuery[T] {
+	q.query.Where(column, operator, value)
+	return q
+}
+
+// OrWhere adds a filter condition OR-ed with the preceding one; see
+// Query.OrWhere.
+func (q *TypedQuery[T]) OrWhere(column, operator string, value interface{}) *TypedQuery[T] {
+	q.query.OrWhere(column, operator, value)
+	return q
+}
+
+// WhereDateBetween adds an inclusive date range filter; see
+// Query.WhereDateBetween.
+func (q *TypedQuery[T]) WhereDateBetween(column string, from, to time.Time) *TypedQuery[T] {
+	q.query.WhereDateBetween(column, from, to)
+	return q
+}
+
+// WhereExistsIn keeps rows whose column value appears in another sheet's
+// column; see Query.WhereExistsIn.
+func (q *TypedQuery[T]) WhereExistsIn(column, otherTable, otherColumn string) *TypedQuery[T] {
+	q.query.WhereExistsIn(column, otherTable, otherColumn)
+	return q
+}
+
+// WhereNotExistsIn keeps rows whose column value is absent from another
+// sheet's column; see Query.WhereNotExistsIn.
+func (q *TypedQuery[T]) WhereNotExistsIn(column, otherTable, otherColumn string) *TypedQuery[T] {
+	q.query.WhereNotExistsIn(column, otherTable, otherColumn)
+	return q
+}
+
+// Limit sets the maximum number of results.
+func (q *TypedQuery[T]) Limit(n int) *TypedQuery[T] {
+	q.query.Limit(n)
+	return q
+}
+
+// LimitPercent keeps the first p percent of the results; see
+// Query.LimitPercent.
+func (q *TypedQuery[T]) LimitPercent(p float64) *TypedQuery[T] {
+	q.query.LimitPercent(p)
+	return q
+}
+
+// OrderBy sets the sort column and direction.
+func (q *TypedQuery[T]) OrderBy(column string, descending bool) *TypedQuery[T] {
+	q.query.OrderBy(column, descending)
+	return q
+}
+
+// NullsLast sorts blank cells last; see Query.NullsLast.
+func (q *TypedQuery[T]) NullsLast(enabled bool) *TypedQuery[T] {
+	q.query.NullsLast(enabled)
+	return q
+}
+
+// Select restricts the columns read and scanned; see Query.Select.
+func (q *TypedQuery[T]) Select(columns ...string) *TypedQuery[T] {
+	q.query.Select(columns...)
+	return q
+}
+
+// CaseInsensitive makes equality and list operators ignore case.
+func (q *TypedQuery[T]) CaseInsensitive(enabled bool) *TypedQuery[T] {
+	q.query.CaseInsensitive(enabled)
+	return q
+}
+
+// ParseColumn sets how comparisons and ordering read a column; see
+// Query.ParseColumn.
+func (q *TypedQuery[T]) ParseColumn(column string, parse func(string) (float64, error)) *TypedQuery[T] {
+	q.query.ParseColumn(column, parse)
+	return q
+}
+
+// StrictScan reports cells that can't be converted; see Query.StrictScan.
+func (q *TypedQuery[T]) StrictScan() *TypedQuery[T] {
+	q.query.StrictScan()
+	return q
+}
+
+// Strict rejects unknown Where and OrderBy columns; see Query.Strict.
+func (q *TypedQuery[T]) Strict() *TypedQuery[T] {
+	q.query.Strict()
+	return q
+}
+
+// ByPosition maps fields to columns by order; see Query.ByPosition.
+func (q *TypedQuery[T]) ByPosition() *TypedQuery[T] {
+	q.query.ByPosition()
+	return q
+}
+
+// Map adds a transformation applied to each record after it is scanned.
+func (q *TypedQuery[T]) Map(fn func(T) T) *TypedQuery[T] {
+	q.query.Map(func(record interface{}) interface{} {
+		return fn(record.(T))
+	})
+	return q
+}
+
+// Timeout bounds how long the query may take when it runs.
+func (q *TypedQuery[T]) Timeout(d time.Duration) *TypedQuery[T] {
+	q.query.Timeout(d)
+	return q
+}
+
+// Reset clears the query's conditions and options; see Query.Reset.
+func (q *TypedQuery[T]) Reset() *TypedQuery[T] {
+	q.query.Reset()
+	return q
+}
+
+// Get executes the query and returns the matching rows. With StrictScan,
+// the rows are returned along with the ScanErrors.
+func (q *TypedQuery[T]) Get(ctx context.Context) ([]T, error) {
+	return Get[T](ctx, q.query)
+}
+
+// GetPage returns at most Limit matching rows and whether more follow; see
+// Query.GetPage.
+func (q *TypedQuery[T]) GetPage(ctx context.Context) ([]T, bool, error) {
+	var results []T
+	hasMore, err := q.query.GetPage(ctx, &results)
+	return results, hasMore, err
+}
+
+// First returns the first matching row, or ErrNoRows if none match.
+func (q *TypedQuery[T]) First(ctx context.Context) (T, error) {
+	return First[T](ctx, q.query)
+}
+
+// Count returns the number of matching rows.
+func (q *TypedQuery[T]) Count(ctx context.Context) (int, error) {
+	return q.query.Count(ctx)
+}
+
+// Distinct returns the distinct values of a column among the matching
+// rows; see Query.Distinct.
+func (q *TypedQuery[T]) Distinct(ctx context.Context, column string) ([]string, error) {
+	return q.query.Distinct(ctx, column)
+}
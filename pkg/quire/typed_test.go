@@ -0,0 +1,215 @@
+package quire
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTypedUsers() (*MockSheetsClient, *TypedTable[TestUser]) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name", "Email", "Age"},
+				{1.0, "Alice", "alice@test.com", 30.0},
+				{2.0, "Bob", "bob@test.com", 25.0},
+				{3.0, "Carol", "carol@test.com", 35.0},
+			}, nil
+		},
+		AppendFunc: func(ctx context.Context, range_ string, values [][]interface{}) error {
+			return nil
+		},
+	}
+	return mock, Typed[TestUser]((&DB{client: mock}).Table("Users"))
+}
+
+func TestTypedTable_GetAll(t *testing.T) {
+	_, users := newTypedUsers()
+
+	got, err := users.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error = %v", err)
+	}
+
+	want := []TestUser{
+		{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+		{ID: 2, Name: "Bob", Email: "bob@test.com", Age: 25},
+		{ID: 3, Name: "Carol", Email: "carol@test.com", Age: 35},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTypedQuery(t *testing.T) {
+	ctx := context.Background()
+	_, users := newTypedUsers()
+
+	got, err := users.Query().
+		Where("Age", ">", 26).
+		OrderBy("Age", true).
+		Map(func(u TestUser) TestUser {
+			u.Name = strings.ToUpper(u.Name)
+			return u
+		}).
+		Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "CAROL" || got[1].Name != "ALICE" {
+		t.Errorf("Get() = %+v, want Carol then Alice, upper-cased", got)
+	}
+
+	first, err := users.Query().Where("Name", "=", "Bob").First(ctx)
+	if err != nil {
+		t.Fatalf("First() unexpected error = %v", err)
+	}
+	if first.ID != 2 {
+		t.Errorf("First() = %+v, want Bob", first)
+	}
+
+	_, err = users.Query().Where("Name", "=", "Dave").First(ctx)
+	if !errors.Is(err, ErrNoRows) {
+		t.Errorf("First() error = %v, want ErrNoRows", err)
+	}
+
+	n, err := users.Query().Where("Age", "<", 31).Count(ctx)
+	if err != nil || n != 2 {
+		t.Errorf("Count() = %d, %v, want 2", n, err)
+	}
+}
+
+func TestTypedTable_Insert(t *testing.T) {
+	mock, users := newTypedUsers()
+
+	if err := users.Insert(context.Background(), []TestUser{{ID: 4, Name: "Dave"}}); err != nil {
+		t.Fatalf("Insert() unexpected error = %v", err)
+	}
+	if len(mock.AppendCalls) != 1 || mock.AppendCalls[0].Values[0][1] != "Dave" {
+		t.Errorf("Insert() append calls = %v", mock.AppendCalls)
+	}
+}
+
+func TestGet(t *testing.T) {
+	_, users := newTypedUsers()
+	q := users.Table().Query().Where("Age", ">=", 30).OrderBy("Age", false)
+
+	got, err := Get[TestUser](context.Background(), q)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+	want := []TestUser{
+		{ID: 1, Name: "Alice", Email: "alice@test.com", Age: 30},
+		{ID: 3, Name: "Carol", Email: "carol@test.com", Age: 35},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	none, err := Get[TestUser](context.Background(), users.Table().Query().Where("Age", ">", 99))
+	if err != nil || len(none) != 0 {
+		t.Errorf("Get() = %v, %v, want no rows", none, err)
+	}
+}
+
+func TestGet_Error(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return nil, errors.New("read failed")
+		},
+	}
+	q := (&DB{client: mock}).Table("Users").Query()
+
+	if _, err := Get[TestUser](context.Background(), q); err == nil {
+		t.Error("Get() expected error")
+	}
+}
+
+func TestFirst(t *testing.T) {
+	ctx := context.Background()
+	_, users := newTypedUsers()
+
+	got, err := First[TestUser](ctx, users.Table().Query().Where("Age", "<", 30))
+	if err != nil {
+		t.Fatalf("First() unexpected error = %v", err)
+	}
+	want := TestUser{ID: 2, Name: "Bob", Email: "bob@test.com", Age: 25}
+	if got != want {
+		t.Errorf("First() = %+v, want %+v", got, want)
+	}
+
+	got, err = First[TestUser](ctx, users.Table().Query().Where("Age", ">", 99))
+	if !errors.Is(err, ErrNoRows) {
+		t.Errorf("First() error = %v, want ErrNoRows", err)
+	}
+	if got != (TestUser{}) {
+		t.Errorf("First() = %+v, want the zero value", got)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	_, users := newTypedUsers()
+
+	var names []string
+	err := Stream(ctx, users.Table().Query().OrderBy("Name", true), func(u TestUser) error {
+		names = append(names, u.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"Carol", "Bob", "Alice"}) {
+		t.Errorf("Stream() visited %v, want Carol, Bob, Alice", names)
+	}
+}
+
+func TestStream_StopsOnError(t *testing.T) {
+	ctx := context.Background()
+	_, users := newTypedUsers()
+
+	errStop := errors.New("stop")
+	var seen []int
+	err := Stream(ctx, users.Table().Query(), func(u TestUser) error {
+		seen = append(seen, u.ID)
+		if u.ID == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Stream() error = %v, want the callback's error", err)
+	}
+	if !reflect.DeepEqual(seen, []int{1, 2}) {
+		t.Errorf("Stream() visited %v, want [1 2]", seen)
+	}
+}
+
+func TestStream_StrictScan(t *testing.T) {
+	mock := &MockSheetsClient{
+		ReadFunc: func(ctx context.Context, range_ string) ([][]interface{}, error) {
+			return [][]interface{}{
+				{"ID", "Name"},
+				{1.0, "Alice"},
+				{"two", "Bob"},
+			}, nil
+		},
+	}
+	q := (&DB{client: mock}).Table("Users").Query().StrictScan()
+
+	calls := 0
+	err := Stream(context.Background(), q, func(u TestUser) error {
+		calls++
+		return nil
+	})
+
+	var scanErrs ScanErrors
+	if !errors.As(err, &scanErrs) || scanErrs[0].Row != 1 {
+		t.Errorf("Stream() error = %v, want ScanErrors for row 1", err)
+	}
+	if calls != 1 {
+		t.Errorf("Stream() called fn %d times, want 1", calls)
+	}
+}
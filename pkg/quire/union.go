@@ -0,0 +1,119 @@
+package quire
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ListTables returns the names of all sheets in the spreadsheet, in tab
+// order.
+func (db *DB) ListTables(ctx context.Context) ([]string, error) {
+	sheetList, err := db.client.ListSheets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sheets: %w", err)
+	}
+
+	names := make([]string, len(sheetList))
+	for i, sheet := range sheetList {
+		names[i] = sheet.Title
+	}
+	return names, nil
+}
+
+// TablesWithPrefix returns a handle for every sheet whose name starts with
+// prefix, in tab order, e.g. the monthly shards "Log_2024_01",
+// "Log_2024_02", ... for prefix "Log_". Query them together with Union.
+func (db *DB) TablesWithPrefix(ctx context.Context, prefix string) ([]*Table, error) {
+	names, err := db.ListTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []*Table
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			tables = append(tables, db.Table(name))
+		}
+	}
+	return tables, nil
+}
+
+// Union builds a read-only query over the rows of several tables, as if
+// they were one sheet. Columns are matched by header name: the result has
+// the first table's columns followed by any new ones from the others, and
+// a row lacks the cells of columns its own sheet doesn't have. The tables
+// are read in a single batch request.
+func Union(first *Table, rest ...*Table) *Query {
+	return &Query{
+		table: first,
+		union: rest,
+	}
+}
+
+// readUnion reads every table of a union query and merges them into one
+// header row and the concatenated data rows.
+func (q *Query) readUnion(ctx context.Context) ([][]interface{}, error) {
+	tables := append([]*Table{q.table}, q.union...)
+
+	var ranges []string
+	for _, t := range tables {
+		if t.snapshot == nil {
+			ranges = append(ranges, t.name)
+		}
+	}
+
+	var results map[string][][]interface{}
+	if len(ranges) > 0 {
+		var err error
+		results, err = q.table.db.client.BatchRead(ctx, ranges)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var headers []interface{}
+	var rows [][]interface{}
+	for _, t := range tables {
+		data := t.snapshot
+		if data == nil {
+			data = results[t.name]
+		}
+		data = t.prepareData(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		// positions maps this table's columns to the merged columns.
+		positions := make([]int, len(data[0]))
+		for i, h := range data[0] {
+			pos := -1
+			for j, existing := range headers {
+				if existing == h {
+					pos = j
+					break
+				}
+			}
+			if pos == -1 {
+				pos = len(headers)
+				headers = append(headers, h)
+			}
+			positions[i] = pos
+		}
+
+		for _, row := range data[1:] {
+			merged := make([]interface{}, len(headers))
+			for i, cell := range row {
+				if i < len(positions) {
+					merged[positions[i]] = cell
+				}
+			}
+			rows = append(rows, merged)
+		}
+	}
+
+	if headers == nil {
+		return nil, nil
+	}
+	return append([][]interface{}{headers}, rows...), nil
+}
@@ -0,0 +1,100 @@
+package quire
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func newShardedMock() *MockSheetsClient {
+	return &MockSheetsClient{
+		ListSheetsFunc: func(ctx context.Context) ([]SheetProperties, error) {
+			return []SheetProperties{
+				{SheetID: 0, Title: "Summary"},
+				{SheetID: 1, Title: "Log_2024_01"},
+				{SheetID: 2, Title: "Log_2024_02"},
+				{SheetID: 3, Title: "Archive_Log_2023"},
+			}, nil
+		},
+		BatchReadFunc: func(ctx context.Context, ranges []string) (map[string][][]interface{}, error) {
+			return map[string][][]interface{}{
+				"Log_2024_01": {
+					{"ID", "Name", "Age"},
+					{1.0, "Alice", 30.0},
+					{2.0, "Bob", 17.0},
+				},
+				"Log_2024_02": {
+					{"Name", "ID", "Email", "Age"},
+					{"Charlie", 3.0, "charlie@test.com", 40.0},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestDB_ListTables(t *testing.T) {
+	db := &DB{client: newShardedMock()}
+
+	names, err := db.ListTables(context.Background())
+	if err != nil {
+		t.Fatalf("ListTables() unexpected error = %v", err)
+	}
+	want := []string{"Summary", "Log_2024_01", "Log_2024_02", "Archive_Log_2023"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("ListTables() = %v, want %v", names, want)
+	}
+}
+
+func TestDB_TablesWithPrefix(t *testing.T) {
+	db := &DB{client: newShardedMock()}
+
+	tables, err := db.TablesWithPrefix(context.Background(), "Log_")
+	if err != nil {
+		t.Fatalf("TablesWithPrefix() unexpected error = %v", err)
+	}
+
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.name)
+	}
+	if want := []string{"Log_2024_01", "Log_2024_02"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("TablesWithPrefix() = %v, want %v", names, want)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	ctx := context.Background()
+	mock := newShardedMock()
+	db := &DB{client: mock}
+
+	tables, err := db.TablesWithPrefix(ctx, "Log_")
+	if err != nil {
+		t.Fatalf("TablesWithPrefix() unexpected error = %v", err)
+	}
+
+	var users []TestUser
+	err = Union(tables[0], tables[1:]...).
+		Where("Age", ">=", 18).
+		OrderBy("Age", true).
+		Get(ctx, &users)
+	if err != nil {
+		t.Fatalf("Get() unexpected error = %v", err)
+	}
+
+	want := []TestUser{
+		{ID: 3, Name: "Charlie", Email: "charlie@test.com", Age: 40},
+		{ID: 1, Name: "Alice", Age: 30},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("Get() = %+v, want %+v", users, want)
+	}
+
+	if len(mock.BatchReadCalls) != 1 || len(mock.ReadCalls) != 0 {
+		t.Errorf("expected a single batch read, got %d batch reads and %d reads", len(mock.BatchReadCalls), len(mock.ReadCalls))
+	}
+
+	count, err := Union(tables[0], tables[1:]...).Count(ctx)
+	if err != nil || count != 3 {
+		t.Errorf("Count() = %d, %v, want 3", count, err)
+	}
+}